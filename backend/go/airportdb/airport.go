@@ -0,0 +1,31 @@
+// Package airportdb loads the non-towered airport universe from
+// OurAirports' CSV exports, with enough runway metadata for the conflict
+// engine to determine the active runway and pattern direction from wind.
+package airportdb
+
+import "github.com/tuedolm/non-towered-safety-system/backend/go/geo"
+
+// Airport is a single non-towered field: a small airport, seaplane base,
+// or heliport with no scheduled service.
+type Airport struct {
+	ICAO        string
+	Name        string
+	Type        string // small_airport, seaplane_base, heliport
+	LatLong     geo.LatLong
+	ElevationFt float64
+	// RadiusNm is how far out to watch for traffic around this airport.
+	// OurAirports doesn't publish this — it's an operational setting
+	// applied uniformly at load time (see DefaultRadiusNm).
+	RadiusNm float64
+	Runways  []Runway
+}
+
+// Runway is one physical strip, identified by its low/high-end idents
+// (e.g. "13"/"31").
+type Runway struct {
+	LowIdent   string
+	HighIdent  string
+	HeadingDeg float64 // true heading of the low-end approach direction
+	LengthFt   float64
+	Surface    string
+}
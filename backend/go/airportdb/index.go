@@ -0,0 +1,177 @@
+package airportdb
+
+import (
+	"math"
+
+	"github.com/tuedolm/non-towered-safety-system/backend/go/geo"
+)
+
+// Index is an in-memory spatial index over a set of Airports, bucketed
+// into 1-degree grid cells. It lets a single regional OpenSky bounding-box
+// query dispatch each returned aircraft to every airport whose radius
+// contains it, rather than issuing one HTTP request per airport. (A real
+// S2 cell or R-tree index would scale further; a 1-degree grid is the
+// simplest structure that keeps this at the density OurAirports' non-
+// towered universe actually has.)
+type Index struct {
+	airports []Airport
+	cells    map[cellKey][]int // indexes into airports, by grid cell
+}
+
+type cellKey struct{ lat, lon int }
+
+// lonCells is the number of 1-degree longitude buckets running all the
+// way around the globe. Bucketing lon mod lonCells (rather than directly
+// off [-180, 180)) means the cell just west of the antimeridian and the
+// cell just east of it are numerically adjacent, so Near's neighbor scan
+// finds them without any special-casing.
+const lonCells = 360
+
+func wrapLonCell(lon int) int {
+	lon %= lonCells
+	if lon < 0 {
+		lon += lonCells
+	}
+	return lon
+}
+
+func cellFor(ll geo.LatLong) cellKey {
+	return cellKey{lat: int(math.Floor(ll.Lat)), lon: wrapLonCell(int(math.Floor(ll.Long)) + 180)}
+}
+
+// NewIndex builds an Index over airports.
+func NewIndex(airports []Airport) *Index {
+	idx := &Index{airports: airports, cells: make(map[cellKey][]int)}
+	for i, a := range airports {
+		key := cellFor(a.LatLong)
+		idx.cells[key] = append(idx.cells[key], i)
+	}
+	return idx
+}
+
+// Airports returns every airport in the index.
+func (idx *Index) Airports() []Airport {
+	return idx.airports
+}
+
+// ByICAO returns the loaded airport with the given ICAO ident, if any.
+func (idx *Index) ByICAO(icao string) (Airport, bool) {
+	for _, a := range idx.airports {
+		if a.ICAO == icao {
+			return a, true
+		}
+	}
+	return Airport{}, false
+}
+
+// Near returns every airport whose configured RadiusNm contains point at
+// altFt (slant range, via geo.Dist3).
+func (idx *Index) Near(point geo.LatLong, altFt float64) []Airport {
+	key := cellFor(point)
+
+	var matches []Airport
+	for dLat := -1; dLat <= 1; dLat++ {
+		for dLon := -1; dLon <= 1; dLon++ {
+			neighbor := cellKey{lat: key.lat + dLat, lon: wrapLonCell(key.lon + dLon)}
+			for _, i := range idx.cells[neighbor] {
+				a := idx.airports[i]
+				if a.LatLong.Dist3(point, altFt) <= a.RadiusNm {
+					matches = append(matches, a)
+				}
+			}
+		}
+	}
+	return matches
+}
+
+// RegionBoundingBox returns a box covering every airport's own radius,
+// suitable for a single OpenSky states/all query over the whole loaded
+// region.
+//
+// geo.BoundingBox returns min.Long > max.Long for a single airport whose
+// own box straddles the antimeridian (the western Aleutians have real
+// OurAirports entries out there); plain numeric min/max over that would
+// silently fold it into an inverted, nonsensical region box. Longitudes
+// are unwrapped relative to the first airport's position before being
+// compared, so both a wrapped per-airport box and the union across
+// airports come out on one continuous number line, then re-wrapped once
+// at the end. OpenSky's states/all only accepts a single non-wrapped
+// bbox, so if the loaded airports themselves end up needing more than a
+// full 360-degree span (disjoint clusters on opposite sides of the
+// globe, not just one region that happens to straddle the dateline) this
+// returns the full -180 to 180 longitude range rather than something
+// degenerate.
+func (idx *Index) RegionBoundingBox() (min, max geo.LatLong) {
+	if len(idx.airports) == 0 {
+		return min, max
+	}
+
+	refLong := idx.airports[0].LatLong.Long
+	first := true
+
+	for _, a := range idx.airports {
+		amin, amax := a.LatLong.BoundingBox(a.RadiusNm)
+
+		minLongU := unwrapLong(amin.Long, refLong)
+		maxLongU := unwrapLong(amax.Long, refLong)
+		if maxLongU < minLongU {
+			// This airport's own box wraps the antimeridian.
+			maxLongU += 360
+		}
+
+		if first {
+			min = geo.LatLong{Lat: amin.Lat, Long: minLongU}
+			max = geo.LatLong{Lat: amax.Lat, Long: maxLongU}
+			first = false
+			continue
+		}
+
+		if amin.Lat < min.Lat {
+			min.Lat = amin.Lat
+		}
+		if amax.Lat > max.Lat {
+			max.Lat = amax.Lat
+		}
+		if minLongU < min.Long {
+			min.Long = minLongU
+		}
+		if maxLongU > max.Long {
+			max.Long = maxLongU
+		}
+	}
+
+	if max.Long-min.Long >= 360 {
+		min.Long, max.Long = -180, 180
+		return min, max
+	}
+
+	shift := min.Long - wrapLong(min.Long)
+	min.Long -= shift
+	max.Long -= shift
+	if max.Long >= 180 {
+		// The region genuinely straddles the antimeridian: report it the
+		// same way geo.BoundingBox reports a single wrapped point, with
+		// min.Long > max.Long, rather than a longitude outside the
+		// package's normal [-180, 180) range.
+		max.Long -= 360
+	}
+	return min, max
+}
+
+// unwrapLong returns the representation of long closest to ref, shifting
+// by whole multiples of 360 degrees so values near the antimeridian
+// compare correctly against a reference on the other side of it.
+func unwrapLong(long, ref float64) float64 {
+	return long + 360*math.Round((ref-long)/360)
+}
+
+// wrapLong wraps long into [-180, 180), matching geo.LatLong's own
+// longitude convention. Duplicated here (rather than exported from geo)
+// since it's a single line only RegionBoundingBox needs.
+func wrapLong(long float64) float64 {
+	long = math.Mod(long+180, 360)
+	if long < 0 {
+		long += 360
+	}
+	return long - 180
+}
@@ -0,0 +1,62 @@
+package airportdb
+
+import (
+	"testing"
+
+	"github.com/tuedolm/non-towered-safety-system/backend/go/geo"
+)
+
+func TestNearMatchesAcrossAntimeridian(t *testing.T) {
+	// Two airports straddling the dateline, a few nm apart.
+	west := Airport{ICAO: "PAWE", LatLong: geo.LatLong{Lat: 52.8, Long: 179.9}, RadiusNm: 10}
+	east := Airport{ICAO: "PAEA", LatLong: geo.LatLong{Lat: 52.8, Long: -179.9}, RadiusNm: 10}
+	idx := NewIndex([]Airport{west, east})
+
+	// A point just east of the dateline should still match the airport
+	// just west of it, since they're genuinely close together.
+	point := geo.LatLong{Lat: 52.8, Long: -179.95}
+	matches := idx.Near(point, 0)
+
+	foundWest := false
+	for _, a := range matches {
+		if a.ICAO == "PAWE" {
+			foundWest = true
+		}
+	}
+	if !foundWest {
+		t.Errorf("Near(%v) = %v, want it to include PAWE across the antimeridian", point, matches)
+	}
+}
+
+func TestRegionBoundingBoxAcrossAntimeridian(t *testing.T) {
+	west := Airport{ICAO: "PAWE", LatLong: geo.LatLong{Lat: 52.8, Long: 179.9}, RadiusNm: 10}
+	east := Airport{ICAO: "PAEA", LatLong: geo.LatLong{Lat: 52.8, Long: -179.9}, RadiusNm: 10}
+	idx := NewIndex([]Airport{west, east})
+
+	min, max := idx.RegionBoundingBox()
+
+	// The combined region is a small span straddling the dateline, not
+	// the (near-)full globe a naive numeric min/max would produce.
+	if min.Long < max.Long {
+		t.Errorf("RegionBoundingBox() min.Long=%v max.Long=%v, want min > max (wrapped across the antimeridian)", min.Long, max.Long)
+	}
+	if min.Long < -180 || min.Long >= 180 {
+		t.Errorf("min.Long = %v, out of [-180, 180) range", min.Long)
+	}
+	if max.Long < -180 || max.Long >= 180 {
+		t.Errorf("max.Long = %v, out of [-180, 180) range", max.Long)
+	}
+}
+
+func TestRegionBoundingBoxSingleRegion(t *testing.T) {
+	// A normal, non-dateline-straddling region should behave exactly as
+	// before: min.Long < max.Long.
+	a := Airport{ICAO: "KRHV", LatLong: geo.LatLong{Lat: 37.3329, Long: -121.8195}, RadiusNm: 10}
+	b := Airport{ICAO: "KPAO", LatLong: geo.LatLong{Lat: 37.4613, Long: -122.1146}, RadiusNm: 10}
+	idx := NewIndex([]Airport{a, b})
+
+	min, max := idx.RegionBoundingBox()
+	if min.Long >= max.Long {
+		t.Errorf("RegionBoundingBox() min.Long=%v max.Long=%v, want min < max for a region nowhere near the antimeridian", min.Long, max.Long)
+	}
+}
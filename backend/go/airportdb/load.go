@@ -0,0 +1,212 @@
+package airportdb
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/tuedolm/non-towered-safety-system/backend/go/geo"
+)
+
+// DefaultRadiusNm is the vicinity radius applied to every loaded airport
+// when the caller doesn't pick a different one.
+const DefaultRadiusNm = 10.0
+
+// Load reads airports.csv and runways.csv (each a local path or an
+// http(s) URL) in OurAirports' format and returns the non-towered
+// universe: small_airport, seaplane_base, and heliport entries with no
+// scheduled service, each tagged with radiusNm as its vicinity radius.
+func Load(airportsSrc, runwaysSrc string, radiusNm float64) ([]Airport, error) {
+	airportRows, err := readCSV(airportsSrc)
+	if err != nil {
+		return nil, fmt.Errorf("airportdb: reading airports: %w", err)
+	}
+	runwayRows, err := readCSV(runwaysSrc)
+	if err != nil {
+		return nil, fmt.Errorf("airportdb: reading runways: %w", err)
+	}
+
+	airports, refToIndex, err := parseAirports(airportRows, radiusNm)
+	if err != nil {
+		return nil, fmt.Errorf("airportdb: parsing airports: %w", err)
+	}
+	if err := attachRunways(airports, refToIndex, runwayRows); err != nil {
+		return nil, fmt.Errorf("airportdb: parsing runways: %w", err)
+	}
+	return airports, nil
+}
+
+func readCSV(src string) ([][]string, error) {
+	var r io.Reader
+	if strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://") {
+		resp, err := http.Get(src)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("fetching %s: status %d", src, resp.StatusCode)
+		}
+		r = resp.Body
+	} else {
+		f, err := os.Open(src)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%s: empty file", src)
+	}
+	return rows, nil
+}
+
+// col returns the index of the named column in an OurAirports header row.
+func col(header []string, name string) int {
+	for i, h := range header {
+		if h == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// requiredCol returns the index of name in header, or an error if
+// OurAirports has dropped or renamed the column since this was written.
+func requiredCol(header []string, name string) (int, error) {
+	i := col(header, name)
+	if i < 0 {
+		return -1, fmt.Errorf("missing required column %q", name)
+	}
+	return i, nil
+}
+
+// parseAirports filters airports.csv down to the non-towered universe
+// and returns it alongside a map from OurAirports' internal "id" to the
+// resulting index, so attachRunways can resolve airport_ref references.
+func parseAirports(rows [][]string, radiusNm float64) ([]Airport, map[string]int, error) {
+	header := rows[0]
+	idxID, err := requiredCol(header, "id")
+	if err != nil {
+		return nil, nil, err
+	}
+	idxIdent, err := requiredCol(header, "ident")
+	if err != nil {
+		return nil, nil, err
+	}
+	idxType, err := requiredCol(header, "type")
+	if err != nil {
+		return nil, nil, err
+	}
+	idxName, err := requiredCol(header, "name")
+	if err != nil {
+		return nil, nil, err
+	}
+	idxLat, err := requiredCol(header, "latitude_deg")
+	if err != nil {
+		return nil, nil, err
+	}
+	idxLon, err := requiredCol(header, "longitude_deg")
+	if err != nil {
+		return nil, nil, err
+	}
+	idxElev, err := requiredCol(header, "elevation_ft")
+	if err != nil {
+		return nil, nil, err
+	}
+	idxSched, err := requiredCol(header, "scheduled_service")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var airports []Airport
+	refToIndex := make(map[string]int)
+
+	for _, row := range rows[1:] {
+		typ := row[idxType]
+		if typ != "small_airport" && typ != "seaplane_base" && typ != "heliport" {
+			continue
+		}
+		if row[idxSched] != "no" {
+			continue
+		}
+
+		lat, _ := strconv.ParseFloat(row[idxLat], 64)
+		lon, _ := strconv.ParseFloat(row[idxLon], 64)
+		elev, _ := strconv.ParseFloat(row[idxElev], 64)
+
+		airports = append(airports, Airport{
+			ICAO:        row[idxIdent],
+			Name:        row[idxName],
+			Type:        typ,
+			LatLong:     geo.LatLong{Lat: lat, Long: lon},
+			ElevationFt: elev,
+			RadiusNm:    radiusNm,
+		})
+		refToIndex[row[idxID]] = len(airports) - 1
+	}
+	return airports, refToIndex, nil
+}
+
+// attachRunways folds runways.csv onto the already-filtered airports,
+// skipping runways whose airport_ref isn't in refToIndex (i.e. belongs to
+// an airport outside the non-towered universe).
+func attachRunways(airports []Airport, refToIndex map[string]int, rows [][]string) error {
+	header := rows[0]
+	idxRef, err := requiredCol(header, "airport_ref")
+	if err != nil {
+		return err
+	}
+	idxLength, err := requiredCol(header, "length_ft")
+	if err != nil {
+		return err
+	}
+	idxSurface, err := requiredCol(header, "surface")
+	if err != nil {
+		return err
+	}
+	idxLeIdent, err := requiredCol(header, "le_ident")
+	if err != nil {
+		return err
+	}
+	idxLeHdg, err := requiredCol(header, "le_heading_degT")
+	if err != nil {
+		return err
+	}
+	idxHeIdent, err := requiredCol(header, "he_ident")
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows[1:] {
+		i, ok := refToIndex[row[idxRef]]
+		if !ok {
+			continue
+		}
+
+		hdg, err := strconv.ParseFloat(row[idxLeHdg], 64)
+		if err != nil {
+			continue // some runways don't publish a true heading
+		}
+		length, _ := strconv.ParseFloat(row[idxLength], 64)
+
+		airports[i].Runways = append(airports[i].Runways, Runway{
+			LowIdent:   row[idxLeIdent],
+			HighIdent:  row[idxHeIdent],
+			HeadingDeg: hdg,
+			LengthFt:   length,
+			Surface:    row[idxSurface],
+		})
+	}
+	return nil
+}
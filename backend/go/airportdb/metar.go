@@ -0,0 +1,72 @@
+package airportdb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// METAR is the subset of a parsed observation the conflict engine needs
+// to determine the active runway.
+type METAR struct {
+	Raw          string
+	WindDirDeg   float64
+	WindSpeedKt  float64
+	VariableWind bool
+}
+
+// windGroupRE matches a METAR wind group, e.g. "18010KT", "18010G18KT",
+// or "VRB03KT".
+var windGroupRE = regexp.MustCompile(`\b(\d{3}|VRB)(\d{2,3})(?:G\d{2,3})?KT\b`)
+
+// FetchMETAR fetches the latest raw METAR for icao from aviationweather.gov
+// and parses its wind group.
+func FetchMETAR(ctx context.Context, icao string) (*METAR, error) {
+	url := fmt.Sprintf("https://aviationweather.gov/api/data/metar?ids=%s&format=raw", icao)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("airportdb: building METAR request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("airportdb: fetching METAR for %s: %w", icao, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("airportdb: METAR for %s: status %d", icao, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("airportdb: reading METAR for %s: %w", icao, err)
+	}
+
+	return ParseMETARWind(strings.TrimSpace(string(body)))
+}
+
+// ParseMETARWind extracts the wind direction and speed from a raw METAR
+// string.
+func ParseMETARWind(raw string) (*METAR, error) {
+	m := windGroupRE.FindStringSubmatch(raw)
+	if m == nil {
+		return nil, fmt.Errorf("airportdb: no wind group found in METAR: %q", raw)
+	}
+
+	metar := &METAR{Raw: raw}
+	if m[1] == "VRB" {
+		metar.VariableWind = true
+	} else {
+		dir, _ := strconv.ParseFloat(m[1], 64)
+		metar.WindDirDeg = dir
+	}
+	speed, _ := strconv.ParseFloat(m[2], 64)
+	metar.WindSpeedKt = speed
+	return metar, nil
+}
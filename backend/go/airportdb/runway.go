@@ -0,0 +1,36 @@
+package airportdb
+
+import "math"
+
+// ActiveRunway returns the runway end best aligned with wind blowing from
+// windDirDeg — the end that minimizes the angle between its approach
+// heading and the wind, so landing traffic flies as close to a headwind
+// as this airport's runways allow. ok is false if the airport has no
+// runway metadata.
+func (a Airport) ActiveRunway(windDirDeg float64) (rw Runway, ident string, headingDeg float64, ok bool) {
+	bestDiff := math.Inf(1)
+	for _, r := range a.Runways {
+		for _, end := range [...]struct {
+			heading float64
+			ident   string
+		}{
+			{r.HeadingDeg, r.LowIdent},
+			{math.Mod(r.HeadingDeg+180, 360), r.HighIdent},
+		} {
+			if diff := angularDiff(windDirDeg, end.heading); diff < bestDiff {
+				bestDiff, rw, ident, headingDeg, ok = diff, r, end.ident, end.heading, true
+			}
+		}
+	}
+	return rw, ident, headingDeg, ok
+}
+
+// angularDiff returns the smallest absolute difference between two
+// compass headings, in [0, 180].
+func angularDiff(a, b float64) float64 {
+	diff := math.Mod(math.Abs(a-b), 360)
+	if diff > 180 {
+		diff = 360 - diff
+	}
+	return diff
+}
@@ -0,0 +1,93 @@
+// Package conflict implements the conflict detection engine for
+// non-towered airport traffic: pairwise closest-point-of-approach
+// projection and traffic-pattern conformance checking.
+package conflict
+
+import "time"
+
+// Track is the subset of aircraft state the engine needs. Callers adapt
+// whatever AircraftState representation they have onto this type rather
+// than conflict depending on an ingestion package.
+type Track struct {
+	ID              string
+	Callsign        string
+	Latitude        float64
+	Longitude       float64
+	AltitudeFt      float64
+	GroundSpeedKt   float64
+	HeadingDeg      float64
+	VerticalRateFpm float64
+}
+
+// AirportRef is the reference point and active runway a set of tracks is
+// evaluated against.
+type AirportRef struct {
+	ICAO             string
+	Latitude         float64
+	Longitude        float64
+	RunwayHeadingDeg float64 // active runway heading, e.g. 130 for runway 13
+}
+
+// AlertKind distinguishes the rule that raised an Alert.
+type AlertKind int
+
+const (
+	// KindLoWC is a loss of well-clear: inside 0.5nm / 500ft within the
+	// CPA time horizon.
+	KindLoWC AlertKind = iota
+	// KindNMAC is a near mid-air collision: inside 500ft / 100ft.
+	KindNMAC
+	// KindOpposingPattern is an aircraft on the runway's extended
+	// centerline flying its reciprocal heading (LegOpposing), head-on with
+	// final/straight-in traffic. Pattern legs flown in the opposite
+	// direction elsewhere around the field do not raise it.
+	KindOpposingPattern
+	// KindStraightInConflict is a straight-in or final aircraft
+	// converging with traffic already established in the pattern.
+	KindStraightInConflict
+)
+
+func (k AlertKind) String() string {
+	switch k {
+	case KindLoWC:
+		return "LoWC"
+	case KindNMAC:
+		return "NMAC"
+	case KindOpposingPattern:
+		return "OPPOSING_PATTERN"
+	case KindStraightInConflict:
+		return "STRAIGHT_IN_CONFLICT"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Alert is a single conflict detection event.
+type Alert struct {
+	Kind             AlertKind
+	AircraftIDs      []string
+	HorizontalMissNm float64
+	VerticalMissFt   float64
+	TimeToCPA        time.Duration
+	Detail           string
+}
+
+// Engine evaluates a snapshot of tracks for conflicts and pattern
+// non-conformance around a single airport.
+type Engine struct {
+	Airport AirportRef
+}
+
+// NewEngine returns an Engine evaluating tracks against airport.
+func NewEngine(airport AirportRef) *Engine {
+	return &Engine{Airport: airport}
+}
+
+// Evaluate runs both closest-point-of-approach and pattern conformance
+// checks over tracks and returns every alert raised.
+func (e *Engine) Evaluate(tracks []Track) []Alert {
+	var alerts []Alert
+	alerts = append(alerts, e.evaluateCPA(tracks)...)
+	alerts = append(alerts, e.evaluatePattern(tracks)...)
+	return alerts
+}
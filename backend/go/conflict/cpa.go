@@ -0,0 +1,113 @@
+package conflict
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// cpaHorizonSec bounds how far forward CPA is projected; beyond this
+	// a predicted close approach is too distant in time to act on.
+	cpaHorizonSec = 40.0
+
+	lowcHorizontalNm = 0.5
+	lowcVerticalFt   = 500.0
+
+	nmacHorizontalFt = 500.0
+	nmacVerticalFt   = 100.0
+
+	nmPerDegLat   = 60.0
+	ftPerNm       = 6076.12
+	ftPerSecPerKt = 1.68781
+)
+
+// enuFeet projects (lat, lon) onto the local tangent plane at origin,
+// returning east/north offsets in feet. This is a flat-earth
+// approximation, adequate over an airport vicinity; the geo package's
+// haversine math has no tangent-plane projection, so it's done here.
+func enuFeet(origin AirportRef, lat, lon float64) (east, north float64) {
+	north = (lat - origin.Latitude) * nmPerDegLat * ftPerNm
+	east = (lon - origin.Longitude) * nmPerDegLat * math.Cos(origin.Latitude*math.Pi/180) * ftPerNm
+	return east, north
+}
+
+// velocityFeetPerSec resolves a ground speed/heading pair into east/north
+// feet-per-second components.
+func velocityFeetPerSec(headingDeg, speedKt float64) (veast, vnorth float64) {
+	rad := headingDeg * math.Pi / 180
+	speedFtSec := speedKt * ftPerSecPerKt
+	return speedFtSec * math.Sin(rad), speedFtSec * math.Cos(rad)
+}
+
+// evaluateCPA projects every pair of tracks forward and raises LoWC/NMAC
+// alerts for pairs that come too close within cpaHorizonSec.
+func (e *Engine) evaluateCPA(tracks []Track) []Alert {
+	var alerts []Alert
+	for i := 0; i < len(tracks); i++ {
+		for j := i + 1; j < len(tracks); j++ {
+			if alert, ok := e.cpaAlert(tracks[i], tracks[j]); ok {
+				alerts = append(alerts, alert)
+			}
+		}
+	}
+	return alerts
+}
+
+// cpaAlert computes the 3D closest point of approach between a and b and
+// reports the most severe threshold it crosses, if any.
+func (e *Engine) cpaAlert(a, b Track) (Alert, bool) {
+	ae, an := enuFeet(e.Airport, a.Latitude, a.Longitude)
+	be, bn := enuFeet(e.Airport, b.Latitude, b.Longitude)
+	aalt, balt := a.AltitudeFt, b.AltitudeFt
+
+	ave, avn := velocityFeetPerSec(a.HeadingDeg, a.GroundSpeedKt)
+	bve, bvn := velocityFeetPerSec(b.HeadingDeg, b.GroundSpeedKt)
+	avalt, bvalt := a.VerticalRateFpm/60.0, b.VerticalRateFpm/60.0
+
+	// Relative position and velocity, b relative to a.
+	dpe, dpn, dpa := be-ae, bn-an, balt-aalt
+	dve, dvn, dva := bve-ave, bvn-avn, bvalt-avalt
+
+	denom := dve*dve + dvn*dvn + dva*dva
+	var tStar float64
+	if denom > 0 {
+		tStar = -(dpe*dve + dpn*dvn + dpa*dva) / denom
+	}
+	if tStar < 0 {
+		tStar = 0
+	}
+	if tStar > cpaHorizonSec {
+		tStar = cpaHorizonSec
+	}
+
+	// Relative position at t*.
+	re := dpe + dve*tStar
+	rn := dpn + dvn*tStar
+	ra := dpa + dva*tStar
+
+	horizontalMissNm := math.Hypot(re, rn) / ftPerNm
+	verticalMissFt := math.Abs(ra)
+
+	switch {
+	case horizontalMissNm*ftPerNm < nmacHorizontalFt && verticalMissFt < nmacVerticalFt:
+		return Alert{
+			Kind:             KindNMAC,
+			AircraftIDs:      []string{a.ID, b.ID},
+			HorizontalMissNm: horizontalMissNm,
+			VerticalMissFt:   verticalMissFt,
+			TimeToCPA:        time.Duration(tStar * float64(time.Second)),
+			Detail:           "projected near mid-air collision",
+		}, true
+	case horizontalMissNm < lowcHorizontalNm && verticalMissFt < lowcVerticalFt:
+		return Alert{
+			Kind:             KindLoWC,
+			AircraftIDs:      []string{a.ID, b.ID},
+			HorizontalMissNm: horizontalMissNm,
+			VerticalMissFt:   verticalMissFt,
+			TimeToCPA:        time.Duration(tStar * float64(time.Second)),
+			Detail:           "projected loss of well-clear",
+		}, true
+	default:
+		return Alert{}, false
+	}
+}
@@ -0,0 +1,51 @@
+package conflict
+
+import "testing"
+
+func TestCPAAlertHeadOnClosingNMAC(t *testing.T) {
+	engine := NewEngine(AirportRef{ICAO: "TEST", Latitude: 37.0, Longitude: -122.0})
+
+	// Two tracks 0.02 degrees of latitude (about 1.2nm) apart on the same
+	// meridian, flying directly at each other at the same altitude and
+	// speed: they should close to
+	// well within the NMAC thresholds (500ft / 100ft) before cpaHorizonSec.
+	a := Track{ID: "a", Latitude: 37.0, Longitude: -122.0, AltitudeFt: 1000, GroundSpeedKt: 90, HeadingDeg: 0}
+	b := Track{ID: "b", Latitude: 37.0 + 0.02, Longitude: -122.0, AltitudeFt: 1000, GroundSpeedKt: 90, HeadingDeg: 180}
+
+	alert, ok := engine.cpaAlert(a, b)
+	if !ok {
+		t.Fatalf("cpaAlert(head-on closing pair) = (_, false), want an alert")
+	}
+	if alert.Kind != KindNMAC {
+		t.Errorf("cpaAlert kind = %v, want KindNMAC", alert.Kind)
+	}
+	if len(alert.AircraftIDs) != 2 || alert.AircraftIDs[0] != "a" || alert.AircraftIDs[1] != "b" {
+		t.Errorf("cpaAlert AircraftIDs = %v, want [a b]", alert.AircraftIDs)
+	}
+}
+
+func TestCPAAlertDivergingPairNoAlert(t *testing.T) {
+	engine := NewEngine(AirportRef{ICAO: "TEST", Latitude: 37.0, Longitude: -122.0})
+
+	// Same starting separation as the closing case, but headed apart:
+	// the projected closest approach should never cross LoWC/NMAC.
+	a := Track{ID: "a", Latitude: 37.0, Longitude: -122.0, AltitudeFt: 1000, GroundSpeedKt: 90, HeadingDeg: 180}
+	b := Track{ID: "b", Latitude: 37.0 + 0.02, Longitude: -122.0, AltitudeFt: 1000, GroundSpeedKt: 90, HeadingDeg: 0}
+
+	if _, ok := engine.cpaAlert(a, b); ok {
+		t.Errorf("cpaAlert(diverging pair) raised an alert, want none")
+	}
+}
+
+func TestCPAAlertVerticallySeparatedNoAlert(t *testing.T) {
+	engine := NewEngine(AirportRef{ICAO: "TEST", Latitude: 37.0, Longitude: -122.0})
+
+	// Co-located laterally but separated by well over the LoWC vertical
+	// threshold: no alert regardless of closure rate.
+	a := Track{ID: "a", Latitude: 37.0, Longitude: -122.0, AltitudeFt: 1000, GroundSpeedKt: 90, HeadingDeg: 0}
+	b := Track{ID: "b", Latitude: 37.0, Longitude: -122.0, AltitudeFt: 3000, GroundSpeedKt: 90, HeadingDeg: 180}
+
+	if _, ok := engine.cpaAlert(a, b); ok {
+		t.Errorf("cpaAlert(vertically separated pair) raised an alert, want none")
+	}
+}
@@ -0,0 +1,148 @@
+package conflict
+
+import "math"
+
+// PatternLeg classifies where in the traffic pattern an aircraft appears
+// to be, from its bearing to the runway threshold and its track.
+type PatternLeg int
+
+const (
+	LegUnknown PatternLeg = iota
+	LegUpwind
+	LegCrosswind
+	LegDownwind
+	LegBase
+	LegFinal
+	LegStraightIn
+	LegOpposing
+)
+
+func (l PatternLeg) String() string {
+	switch l {
+	case LegUpwind:
+		return "upwind"
+	case LegCrosswind:
+		return "crosswind"
+	case LegDownwind:
+		return "downwind"
+	case LegBase:
+		return "base"
+	case LegFinal:
+		return "final"
+	case LegStraightIn:
+		return "straight-in"
+	case LegOpposing:
+		return "opposing"
+	default:
+		return "unknown"
+	}
+}
+
+const (
+	patternRangeNm   = 15.0 // beyond this, a track isn't considered pattern traffic
+	nearFieldNm      = 3.0  // crosswind/base/downwind are flown close to the field
+	finalRangeNm     = 1.5
+	headingTolDeg    = 30.0
+	centerlineTolDeg = 15.0
+)
+
+// normalizeDeg wraps deg into (-180, 180].
+func normalizeDeg(deg float64) float64 {
+	deg = math.Mod(deg, 360)
+	if deg > 180 {
+		deg -= 360
+	}
+	if deg <= -180 {
+		deg += 360
+	}
+	return deg
+}
+
+// ClassifyLeg estimates which leg of the pattern t is flying relative to
+// airport's active runway. It assumes standard left-hand traffic, since
+// AirportRef doesn't yet carry handedness.
+func ClassifyLeg(airport AirportRef, t Track) PatternLeg {
+	east, north := enuFeet(airport, t.Latitude, t.Longitude)
+	distNm := math.Hypot(east, north) / ftPerNm
+	if distNm > patternRangeNm {
+		return LegUnknown
+	}
+
+	// Bearing from the aircraft back to the field, and where that bearing
+	// sits relative to the runway's extended centerline.
+	bearingToField := normalizeDeg(math.Atan2(-east, -north) * 180 / math.Pi)
+	fieldRel := normalizeDeg(bearingToField - airport.RunwayHeadingDeg)
+	hdgRel := normalizeDeg(t.HeadingDeg - airport.RunwayHeadingDeg)
+
+	onCenterline := math.Abs(fieldRel) <= centerlineTolDeg
+	flyingRunwayHdg := math.Abs(hdgRel) <= headingTolDeg
+	flyingReciprocal := math.Abs(normalizeDeg(hdgRel-180)) <= headingTolDeg
+
+	switch {
+	case flyingRunwayHdg && onCenterline:
+		if distNm <= finalRangeNm {
+			return LegFinal
+		}
+		return LegStraightIn
+	case flyingReciprocal && onCenterline:
+		// Head-on with final/straight-in traffic.
+		return LegOpposing
+	case flyingRunwayHdg:
+		return LegUpwind
+	case flyingReciprocal && distNm <= nearFieldNm:
+		return LegDownwind
+	case math.Abs(normalizeDeg(hdgRel+90)) <= headingTolDeg && distNm <= nearFieldNm:
+		return LegCrosswind
+	case math.Abs(normalizeDeg(hdgRel-90)) <= headingTolDeg && distNm <= nearFieldNm:
+		return LegBase
+	default:
+		return LegUnknown
+	}
+}
+
+// evaluatePattern flags aircraft flying opposite the established traffic
+// flow, and straight-in/final traffic converging with aircraft already
+// established in the pattern — the core non-towered safety concern.
+func (e *Engine) evaluatePattern(tracks []Track) []Alert {
+	type classified struct {
+		track Track
+		leg   PatternLeg
+	}
+
+	legs := make([]classified, 0, len(tracks))
+	for _, t := range tracks {
+		legs = append(legs, classified{t, ClassifyLeg(e.Airport, t)})
+	}
+
+	var alerts []Alert
+	for _, c := range legs {
+		if c.leg == LegOpposing {
+			alerts = append(alerts, Alert{
+				Kind:        KindOpposingPattern,
+				AircraftIDs: []string{c.track.ID},
+				Detail:      "aircraft flying the runway reciprocal heading aligned with the extended centerline",
+			})
+		}
+	}
+
+	for _, c := range legs {
+		if c.leg != LegStraightIn && c.leg != LegFinal {
+			continue
+		}
+		for _, other := range legs {
+			if other.track.ID == c.track.ID {
+				continue
+			}
+			switch other.leg {
+			case LegDownwind, LegBase, LegCrosswind, LegUpwind:
+				alerts = append(alerts, Alert{
+					Kind:        KindStraightInConflict,
+					AircraftIDs: []string{c.track.ID, other.track.ID},
+					Detail:      c.leg.String() + " traffic converging with aircraft established in the pattern",
+				})
+			}
+		}
+	}
+
+	return alerts
+}
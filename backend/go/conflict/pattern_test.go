@@ -0,0 +1,81 @@
+package conflict
+
+import (
+	"math"
+	"testing"
+)
+
+// testAirport is a runway aligned true north (heading 0), so "south of
+// the field, heading north" sits on the final approach centerline.
+var testAirport = AirportRef{ICAO: "TEST", Latitude: 37.0, Longitude: -122.0, RunwayHeadingDeg: 0}
+
+// trackAt returns a Track nmNorth/nmEast of testAirport flying headingDeg.
+func trackAt(nmNorth, nmEast, headingDeg float64) Track {
+	cosLat := math.Cos(testAirport.Latitude * math.Pi / 180)
+	return Track{
+		ID:         "t",
+		Latitude:   testAirport.Latitude + nmNorth/nmPerDegLat,
+		Longitude:  testAirport.Longitude + nmEast/(nmPerDegLat*cosLat),
+		HeadingDeg: headingDeg,
+	}
+}
+
+func TestClassifyLegFinal(t *testing.T) {
+	// 1nm south of the field, heading north (runway heading): inside
+	// finalRangeNm and on the extended centerline.
+	tr := trackAt(-1, 0, 0)
+	if leg := ClassifyLeg(testAirport, tr); leg != LegFinal {
+		t.Errorf("ClassifyLeg(final position) = %v, want LegFinal", leg)
+	}
+}
+
+func TestClassifyLegStraightIn(t *testing.T) {
+	// Same centerline alignment as final, but beyond finalRangeNm.
+	tr := trackAt(-5, 0, 0)
+	if leg := ClassifyLeg(testAirport, tr); leg != LegStraightIn {
+		t.Errorf("ClassifyLeg(straight-in position) = %v, want LegStraightIn", leg)
+	}
+}
+
+func TestClassifyLegOpposing(t *testing.T) {
+	// Same position as final (south of the field, on centerline), but
+	// flying the runway's reciprocal heading: head-on with arriving traffic.
+	tr := trackAt(-1, 0, 180)
+	if leg := ClassifyLeg(testAirport, tr); leg != LegOpposing {
+		t.Errorf("ClassifyLeg(opposing position) = %v, want LegOpposing", leg)
+	}
+}
+
+func TestClassifyLegDownwind(t *testing.T) {
+	// Abeam the field, flying the runway's reciprocal heading, off the
+	// extended centerline: the classic downwind leg.
+	tr := trackAt(0, 2, 180)
+	if leg := ClassifyLeg(testAirport, tr); leg != LegDownwind {
+		t.Errorf("ClassifyLeg(downwind position) = %v, want LegDownwind", leg)
+	}
+}
+
+func TestClassifyLegBase(t *testing.T) {
+	// Near the field, flying perpendicular to the runway, turning inbound
+	// from downwind to final.
+	tr := trackAt(1, 0, 90)
+	if leg := ClassifyLeg(testAirport, tr); leg != LegBase {
+		t.Errorf("ClassifyLeg(base position) = %v, want LegBase", leg)
+	}
+}
+
+func TestClassifyLegCrosswind(t *testing.T) {
+	// Near the field, flying perpendicular to the runway the other way,
+	// just after departure off the upwind leg.
+	tr := trackAt(1, 0, 270)
+	if leg := ClassifyLeg(testAirport, tr); leg != LegCrosswind {
+		t.Errorf("ClassifyLeg(crosswind position) = %v, want LegCrosswind", leg)
+	}
+}
+
+func TestClassifyLegUnknownBeyondPatternRange(t *testing.T) {
+	tr := trackAt(-20, 0, 0)
+	if leg := ClassifyLeg(testAirport, tr); leg != LegUnknown {
+		t.Errorf("ClassifyLeg(beyond pattern range) = %v, want LegUnknown", leg)
+	}
+}
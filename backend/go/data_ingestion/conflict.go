@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/tuedolm/non-towered-safety-system/backend/go/airportdb"
+	"github.com/tuedolm/non-towered-safety-system/backend/go/conflict"
+)
+
+// evaluateConflicts groups fused by the airport vicinity each aircraft was
+// tagged with, runs the conflict engine once per airport with traffic, and
+// logs whatever alerts it raises.
+func evaluateConflicts(ctx context.Context, logger *log.Logger, index *airportdb.Index, fused []AircraftState) {
+	byAirport := make(map[string][]AircraftState)
+	for _, a := range fused {
+		if a.AirportVicinity == "" {
+			continue
+		}
+		byAirport[a.AirportVicinity] = append(byAirport[a.AirportVicinity], a)
+	}
+
+	for icao, states := range byAirport {
+		airport, ok := index.ByICAO(icao)
+		if !ok {
+			continue
+		}
+
+		engine := conflict.NewEngine(conflict.AirportRef{
+			ICAO:             airport.ICAO,
+			Latitude:         airport.LatLong.Lat,
+			Longitude:        airport.LatLong.Long,
+			RunwayHeadingDeg: activeRunwayHeading(ctx, logger, airport),
+		})
+
+		tracks := make([]conflict.Track, len(states))
+		for i, s := range states {
+			tracks[i] = adaptTrack(s)
+		}
+
+		for _, alert := range engine.Evaluate(tracks) {
+			logger.Printf("CONFLICT [%s] %s: %s (aircraft: %v)", airport.ICAO, alert.Kind, alert.Detail, alert.AircraftIDs)
+		}
+	}
+}
+
+// adaptTrack converts a fused AircraftState into the conflict package's
+// decoupled Track type.
+func adaptTrack(a AircraftState) conflict.Track {
+	return conflict.Track{
+		ID:              a.Icao24,
+		Callsign:        a.Callsign,
+		Latitude:        a.Latitude,
+		Longitude:       a.Longitude,
+		AltitudeFt:      a.Altitude,
+		GroundSpeedKt:   a.Velocity,
+		HeadingDeg:      a.Heading,
+		VerticalRateFpm: a.VerticalRate,
+	}
+}
+
+// activeRunwayHeading determines airport's active runway heading from its
+// latest METAR wind, falling back to its first published runway's heading
+// if no wind observation, a variable wind, or runway metadata is
+// available. Pattern conformance degrades gracefully in that case rather
+// than blocking the CPA checks, which don't depend on runway heading at
+// all.
+func activeRunwayHeading(ctx context.Context, logger *log.Logger, airport airportdb.Airport) float64 {
+	if metar, err := airportdb.FetchMETAR(ctx, airport.ICAO); err == nil {
+		if !metar.VariableWind {
+			if _, _, heading, ok := airport.ActiveRunway(metar.WindDirDeg); ok {
+				return heading
+			}
+		}
+	} else {
+		logger.Printf("conflict: fetching METAR for %s: %v", airport.ICAO, err)
+	}
+	if len(airport.Runways) > 0 {
+		return airport.Runways[0].HeadingDeg
+	}
+	return 0
+}
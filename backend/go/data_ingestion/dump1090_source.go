@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tuedolm/non-towered-safety-system/backend/go/airportdb"
+	"github.com/tuedolm/non-towered-safety-system/backend/go/geo"
+)
+
+// Dump1090Source connects to a local dump1090 SBS-1 (BaseStation) feed,
+// typically on port 30003, and accumulates aircraft state from its CSV
+// "MSG" lines until the next Poll.
+type Dump1090Source struct {
+	addr  string
+	index *airportdb.Index
+
+	mu     sync.Mutex
+	tracks map[string]AircraftState
+}
+
+// NewDump1090Source returns a source that dials addr (host:port) for the
+// dump1090 BaseStation feed. Call Run in its own goroutine before Poll is
+// used so there's data to report. index is used to tag each report with
+// the airport vicinities it falls within, same as OpenSkySource.
+func NewDump1090Source(addr string, index *airportdb.Index) *Dump1090Source {
+	return &Dump1090Source{addr: addr, index: index, tracks: make(map[string]AircraftState)}
+}
+
+// Run dials the feed and consumes it until ctx is done, reconnecting on
+// error after a short backoff.
+func (s *Dump1090Source) Run(ctx context.Context) {
+	for ctx.Err() == nil {
+		if err := s.consume(ctx); err != nil {
+			time.Sleep(5 * time.Second)
+		}
+	}
+}
+
+func (s *Dump1090Source) consume(ctx context.Context) error {
+	conn, err := net.Dial("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		s.ingest(scanner.Text())
+	}
+	return scanner.Err()
+}
+
+// ingest parses one SBS-1 "MSG" line:
+// MSG,type,sid,aid,icao24,fid,date,time,date,time,callsign,alt,gs,trk,lat,lon,vr,squawk,alert,emergency,spi,onground
+func (s *Dump1090Source) ingest(line string) {
+	fields := strings.Split(line, ",")
+	if len(fields) < 22 || fields[0] != "MSG" {
+		return
+	}
+	icao24 := strings.ToLower(strings.TrimSpace(fields[4]))
+	if icao24 == "" {
+		return
+	}
+
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state := s.tracks[icao24]
+	state.Icao24 = icao24
+	state.Source = Source1090ES
+	state.OnGround = strings.TrimSpace(fields[21]) == "-1"
+	state.LastContact = now.Unix()
+	state.Timestamp = now
+	state.Fields.LastSeen = now
+
+	if cs := strings.TrimSpace(fields[10]); cs != "" {
+		state.Callsign = cs
+		state.Fields.LastCallsign = now
+	}
+	if alt, err := strconv.ParseFloat(strings.TrimSpace(fields[11]), 64); err == nil {
+		state.Altitude = alt
+		state.Fields.LastAlt = now
+	}
+	if lat, err := strconv.ParseFloat(strings.TrimSpace(fields[14]), 64); err == nil {
+		state.Latitude = lat
+		state.Fields.LastPos = now
+	}
+	if lon, err := strconv.ParseFloat(strings.TrimSpace(fields[15]), 64); err == nil {
+		state.Longitude = lon
+	}
+	if gs, err := strconv.ParseFloat(strings.TrimSpace(fields[12]), 64); err == nil {
+		state.Velocity = gs
+		state.Fields.LastVelocity = now
+	}
+	if trk, err := strconv.ParseFloat(strings.TrimSpace(fields[13]), 64); err == nil {
+		state.Heading = trk
+	}
+	if vr, err := strconv.ParseFloat(strings.TrimSpace(fields[16]), 64); err == nil {
+		state.VerticalRate = vr
+	}
+
+	s.tracks[icao24] = state
+}
+
+// Poll returns every aircraft state the feed has updated within
+// staleAfter, whether or not an earlier Poll already returned it,
+// evicting any track that's gone stale and fanning each surviving one out to the airport vicinities it falls within, same as
+// OpenSkySource.Poll.
+func (s *Dump1090Source) Poll(ctx context.Context) ([]AircraftState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cutoff := time.Now().Add(-staleAfter)
+	var out []AircraftState
+	for icao, state := range s.tracks {
+		if state.Fields.LastSeen.Before(cutoff) {
+			delete(s.tracks, icao)
+			continue
+		}
+		point := geo.LatLong{Lat: state.Latitude, Long: state.Longitude}
+		for _, airport := range s.index.Near(point, state.Altitude) {
+			dispatched := state
+			dispatched.AirportVicinity = airport.ICAO
+			out = append(out, dispatched)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/tuedolm/non-towered-safety-system/backend/go/airportdb"
+	"github.com/tuedolm/non-towered-safety-system/backend/go/geo"
+)
+
+// uatMessage is one line of dump978's newline-delimited JSON UAT traffic
+// stream.
+type uatMessage struct {
+	Address  string  `json:"address"`
+	Callsign string  `json:"callsign"`
+	Lat      float64 `json:"lat"`
+	Lon      float64 `json:"lon"`
+	Altitude float64 `json:"altitude"`
+	Speed    float64 `json:"speed"`
+	Track    float64 `json:"track"`
+	VertRate float64 `json:"vert_rate"`
+	OnGround bool    `json:"on_ground"`
+}
+
+// Dump978Source connects to a local dump978 UAT JSON feed and accumulates
+// aircraft state until the next Poll.
+type Dump978Source struct {
+	addr  string
+	index *airportdb.Index
+
+	mu     sync.Mutex
+	tracks map[string]AircraftState
+}
+
+// NewDump978Source returns a source that dials addr (host:port) for the
+// dump978 JSON feed. Call Run in its own goroutine before Poll is used.
+// index is used to tag each report with the airport vicinities it falls
+// within, same as OpenSkySource.
+func NewDump978Source(addr string, index *airportdb.Index) *Dump978Source {
+	return &Dump978Source{addr: addr, index: index, tracks: make(map[string]AircraftState)}
+}
+
+// Run dials the feed and consumes it until ctx is done, reconnecting on
+// error after a short backoff.
+func (s *Dump978Source) Run(ctx context.Context) {
+	for ctx.Err() == nil {
+		if err := s.consume(ctx); err != nil {
+			time.Sleep(5 * time.Second)
+		}
+	}
+}
+
+func (s *Dump978Source) consume(ctx context.Context) error {
+	conn, err := net.Dial("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	dec := json.NewDecoder(bufio.NewReader(conn))
+	for {
+		var msg uatMessage
+		if err := dec.Decode(&msg); err != nil {
+			return err
+		}
+		s.ingest(msg)
+	}
+}
+
+func (s *Dump978Source) ingest(msg uatMessage) {
+	if msg.Address == "" {
+		return
+	}
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tracks[msg.Address] = AircraftState{
+		Icao24:       msg.Address,
+		Callsign:     msg.Callsign,
+		Latitude:     msg.Lat,
+		Longitude:    msg.Lon,
+		Altitude:     msg.Altitude,
+		Velocity:     msg.Speed,
+		Heading:      msg.Track,
+		VerticalRate: msg.VertRate,
+		OnGround:     msg.OnGround,
+		LastContact:  now.Unix(),
+		Timestamp:    now,
+		Source:       SourceUAT,
+		Fields: FieldTimestamps{
+			LastPos:      now,
+			LastAlt:      now,
+			LastVelocity: now,
+			LastCallsign: now,
+			LastSeen:     now,
+		},
+	}
+}
+
+// Poll returns every aircraft state the feed has updated within
+// staleAfter, whether or not an earlier Poll already returned it,
+// evicting any track that's gone stale and fanning each surviving one out to the airport vicinities it falls within, same as
+// OpenSkySource.Poll.
+func (s *Dump978Source) Poll(ctx context.Context) ([]AircraftState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cutoff := time.Now().Add(-staleAfter)
+	var out []AircraftState
+	for icao, state := range s.tracks {
+		if state.Fields.LastSeen.Before(cutoff) {
+			delete(s.tracks, icao)
+			continue
+		}
+		point := geo.LatLong{Lat: state.Latitude, Long: state.Longitude}
+		for _, airport := range s.index.Near(point, state.Altitude) {
+			dispatched := state
+			dispatched.AirportVicinity = airport.ICAO
+			out = append(out, dispatched)
+		}
+	}
+	return out, nil
+}
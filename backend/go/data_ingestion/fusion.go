@@ -0,0 +1,90 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// staleAfter bounds how long a track may go without an update before it's
+// dropped: long enough to ride out a missed poll, short enough that an
+// aircraft that's landed or flown out of range stops being reported (and
+// stops generating conflict alerts) within a few minutes instead of for
+// the life of the process.
+const staleAfter = 5 * time.Minute
+
+// Fusion merges AircraftState updates from multiple TrafficSources, keyed
+// by ICAO24. Each field is kept independently fresh: a dump1090 altitude
+// report doesn't overwrite a more recent OpenSky position, and vice versa.
+type Fusion struct {
+	mu     sync.Mutex
+	tracks map[string]AircraftState
+}
+
+// NewFusion returns an empty Fusion ready to merge updates into.
+func NewFusion() *Fusion {
+	return &Fusion{tracks: make(map[string]AircraftState)}
+}
+
+// Merge folds update into the track for its ICAO24 and returns the
+// resulting fused state.
+func (f *Fusion) Merge(update AircraftState) AircraftState {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	existing, ok := f.tracks[update.Icao24]
+	if !ok {
+		f.tracks[update.Icao24] = update
+		return update
+	}
+
+	merged := existing
+	if update.Fields.LastPos.After(existing.Fields.LastPos) {
+		merged.Latitude = update.Latitude
+		merged.Longitude = update.Longitude
+		merged.Fields.LastPos = update.Fields.LastPos
+	}
+	if update.Fields.LastAlt.After(existing.Fields.LastAlt) {
+		merged.Altitude = update.Altitude
+		merged.Fields.LastAlt = update.Fields.LastAlt
+	}
+	if update.Fields.LastVelocity.After(existing.Fields.LastVelocity) {
+		merged.Velocity = update.Velocity
+		merged.Heading = update.Heading
+		merged.VerticalRate = update.VerticalRate
+		merged.Fields.LastVelocity = update.Fields.LastVelocity
+	}
+	if update.Fields.LastCallsign.After(existing.Fields.LastCallsign) {
+		merged.Callsign = update.Callsign
+		merged.Fields.LastCallsign = update.Fields.LastCallsign
+	}
+	if update.Fields.LastSeen.After(existing.Fields.LastSeen) {
+		merged.Source = update.Source
+		merged.Signal = update.Signal
+		merged.OnGround = update.OnGround
+		merged.LastContact = update.LastContact
+		merged.Timestamp = update.Timestamp
+		merged.AirportVicinity = update.AirportVicinity
+		merged.Fields.LastSeen = update.Fields.LastSeen
+	}
+
+	f.tracks[update.Icao24] = merged
+	return merged
+}
+
+// Snapshot returns every currently-tracked aircraft, evicting any track
+// that's gone stale (see staleAfter) along the way.
+func (f *Fusion) Snapshot() []AircraftState {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	cutoff := time.Now().Add(-staleAfter)
+	out := make([]AircraftState, 0, len(f.tracks))
+	for icao, state := range f.tracks {
+		if state.Fields.LastSeen.Before(cutoff) {
+			delete(f.tracks, icao)
+			continue
+		}
+		out = append(out, state)
+	}
+	return out
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFusionMergeKeepsNewerVicinityOverOlder(t *testing.T) {
+	f := NewFusion()
+	t0 := time.Now().Add(-time.Minute)
+	t1 := t0.Add(time.Second)
+
+	f.Merge(AircraftState{
+		Icao24:          "abc123",
+		AirportVicinity: "KRHV",
+		Fields:          FieldTimestamps{LastSeen: t0},
+	})
+	merged := f.Merge(AircraftState{
+		Icao24:          "abc123",
+		AirportVicinity: "KPAO",
+		Fields:          FieldTimestamps{LastSeen: t1},
+	})
+
+	if merged.AirportVicinity != "KPAO" {
+		t.Errorf("AirportVicinity = %q, want %q (the newer update)", merged.AirportVicinity, "KPAO")
+	}
+}
+
+func TestFusionMergeDoesNotBlankVicinityWithStaleUpdate(t *testing.T) {
+	f := NewFusion()
+	t0 := time.Now().Add(-time.Minute)
+	t1 := t0.Add(time.Second)
+
+	f.Merge(AircraftState{
+		Icao24:          "abc123",
+		AirportVicinity: "KRHV",
+		Fields:          FieldTimestamps{LastSeen: t1},
+	})
+	// An update that arrives with an older LastSeen than what's already
+	// merged (e.g. a slow source catching up) must not overwrite the
+	// vicinity a fresher update already established.
+	merged := f.Merge(AircraftState{
+		Icao24:          "abc123",
+		AirportVicinity: "",
+		Fields:          FieldTimestamps{LastSeen: t0},
+	})
+
+	if merged.AirportVicinity != "KRHV" {
+		t.Errorf("AirportVicinity = %q, want %q (the existing, fresher value)", merged.AirportVicinity, "KRHV")
+	}
+}
+
+func TestFusionMergeFieldsIndependentlyFresh(t *testing.T) {
+	f := NewFusion()
+	t0 := time.Now().Add(-time.Minute)
+	t1 := t0.Add(time.Second)
+
+	// First update: position only.
+	f.Merge(AircraftState{
+		Icao24:    "abc123",
+		Latitude:  37.1,
+		Longitude: -122.1,
+		Fields:    FieldTimestamps{LastPos: t0, LastSeen: t0},
+	})
+	// Second, later update: altitude only, with an older position
+	// timestamp than the first update's.
+	merged := f.Merge(AircraftState{
+		Icao24:    "abc123",
+		Latitude:  0,
+		Longitude: 0,
+		Altitude:  5500,
+		Fields:    FieldTimestamps{LastPos: t0.Add(-time.Hour), LastAlt: t1, LastSeen: t1},
+	})
+
+	if merged.Latitude != 37.1 || merged.Longitude != -122.1 {
+		t.Errorf("position = (%v, %v), want the first update's position preserved", merged.Latitude, merged.Longitude)
+	}
+	if merged.Altitude != 5500 {
+		t.Errorf("Altitude = %v, want 5500 from the second update", merged.Altitude)
+	}
+}
+
+func TestFusionSnapshotEvictsStaleTracks(t *testing.T) {
+	f := NewFusion()
+	now := time.Now()
+
+	f.Merge(AircraftState{Icao24: "fresh", Fields: FieldTimestamps{LastSeen: now}})
+	f.Merge(AircraftState{Icao24: "stale", Fields: FieldTimestamps{LastSeen: now.Add(-staleAfter - time.Minute)}})
+
+	snap := f.Snapshot()
+	if len(snap) != 1 || snap[0].Icao24 != "fresh" {
+		t.Fatalf("Snapshot() = %v, want only the fresh track", snap)
+	}
+
+	// The stale track must actually be gone, not just filtered out of
+	// this one Snapshot call.
+	if again := f.Snapshot(); len(again) != 1 {
+		t.Errorf("Snapshot() after eviction = %v, want the stale track to stay evicted", again)
+	}
+}
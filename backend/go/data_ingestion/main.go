@@ -1,30 +1,45 @@
 package main
 
 import (
-	"encoding/json"
-	"fmt"
+	"context"
 	"log"
-	"net/http"
 	"os"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/tuedolm/non-towered-safety-system/backend/go/airportdb"
+	"github.com/tuedolm/non-towered-safety-system/backend/go/gdl90"
 )
 
 // AircraftState represents the state of an aircraft as reported by ADS-B
+// (or UAT), after fusion across whichever sources are currently reporting
+// on it.
 type AircraftState struct {
-	Icao24         string    `json:"icao24"`
-	Callsign       string    `json:"callsign"`
-	OriginCountry  string    `json:"origin_country"`
-	Latitude       float64   `json:"latitude,omitempty"`
-	Longitude      float64   `json:"longitude,omitempty"`
-	Altitude       float64   `json:"altitude,omitempty"`
-	Velocity       float64   `json:"velocity,omitempty"`
-	Heading        float64   `json:"heading,omitempty"`
-	VerticalRate   float64   `json:"vertical_rate,omitempty"`
-	OnGround       bool      `json:"on_ground"`
-	LastContact    int64     `json:"last_contact"`
-	TimePosition   int64     `json:"time_position,omitempty"`
-	Timestamp      time.Time `json:"-"`
+	Icao24          string    `json:"icao24"`
+	Callsign        string    `json:"callsign"`
+	OriginCountry   string    `json:"origin_country"`
+	Latitude        float64   `json:"latitude,omitempty"`
+	Longitude       float64   `json:"longitude,omitempty"`
+	Altitude        float64   `json:"altitude,omitempty"`
+	Velocity        float64   `json:"velocity,omitempty"`
+	Heading         float64   `json:"heading,omitempty"`
+	VerticalRate    float64   `json:"vertical_rate,omitempty"`
+	OnGround        bool      `json:"on_ground"`
+	LastContact     int64     `json:"last_contact"`
+	TimePosition    int64     `json:"time_position,omitempty"`
+	Timestamp       time.Time `json:"-"`
 	AirportVicinity string    `json:"-"`
+
+	// Source is the feed that most recently updated this track.
+	Source Source `json:"source"`
+	// Signal is the per-target signal level reported by the source, if
+	// any (e.g. Stratux's SignalLevel). Zero if the source doesn't report it.
+	Signal float64 `json:"signal,omitempty"`
+	// Fields records when each independently-reported field was last
+	// updated, since Mode S transmits position, altitude, velocity, and
+	// callsign in separate messages.
+	Fields FieldTimestamps `json:"-"`
 }
 
 // OpenSkyResponse represents the response from the OpenSky Network API
@@ -33,104 +48,199 @@ type OpenSkyResponse struct {
 	States [][]interface{} `json:"states"`
 }
 
-// AirportBoundary defines the geographical boundaries of an airport vicinity
-type AirportBoundary struct {
-	ICAO      string  `json:"icao"`
-	Name      string  `json:"name"`
-	Latitude  float64 `json:"latitude"`
-	Longitude float64 `json:"longitude"`
-	Radius    float64 `json:"radius"` // in nautical miles
-}
-
-var airports = []AirportBoundary{
-	// Example airports - these would be loaded from a database in production
-	{ICAO: "KRHV", Name: "Reid-Hillview", Latitude: 37.3329, Longitude: -121.8195, Radius: 10},
-	{ICAO: "KPAO", Name: "Palo Alto", Latitude: 37.4613, Longitude: -122.1146, Radius: 10},
-}
+// defaultAirportsCSV and defaultRunwaysCSV point at David Megginson's
+// mirror of OurAirports' data, refreshed daily.
+const (
+	defaultAirportsCSV = "https://davidmegginson.github.io/ourairports-data/airports.csv"
+	defaultRunwaysCSV  = "https://davidmegginson.github.io/ourairports-data/runways.csv"
+)
 
 func main() {
 	logger := log.New(os.Stdout, "DATA-INGEST: ", log.LstdFlags)
-	logger.Println("Starting OpenSky Network ADS-B data ingestion service")
 
 	// In a production environment, these would be loaded from environment variables
 	openSkyUsername := os.Getenv("OPENSKY_USERNAME")
 	openSkyPassword := os.Getenv("OPENSKY_PASSWORD")
+	openSkyClient := NewOpenSkyClient(openSkyUsername, openSkyPassword)
+
+	airportsSrc := envOr("AIRPORTDB_AIRPORTS_CSV", defaultAirportsCSV)
+	runwaysSrc := envOr("AIRPORTDB_RUNWAYS_CSV", defaultRunwaysCSV)
+	radiusNm := airportdb.DefaultRadiusNm
+	if v := os.Getenv("AIRPORT_RADIUS_NM"); v != "" {
+		if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+			radiusNm = parsed
+		}
+	}
+
+	replayOpts, replay, err := parseReplayFlags(os.Args[1:])
+	if err != nil {
+		logger.Fatalf("invalid flags: %v", err)
+	}
+	if replay {
+		runReplay(logger, openSkyClient, replayOpts, airportsSrc, runwaysSrc, radiusNm)
+		return
+	}
+
+	logger.Println("Starting ADS-B data ingestion service")
+
+	loadedAirports, err := airportdb.Load(airportsSrc, runwaysSrc, radiusNm)
+	if err != nil {
+		logger.Fatalf("loading airport database: %v", err)
+	}
+	index := airportdb.NewIndex(loadedAirports)
+	logger.Printf("Loaded %d non-towered airports", len(loadedAirports))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sources := []TrafficSource{
+		NewOpenSkySource(logger, openSkyClient, index),
+	}
+
+	// Local receiver feeds are opt-in: they fill in the coverage OpenSky
+	// misses below ~1000 ft AGL at fields with an SDR on site.
+	if addr := os.Getenv("DUMP1090_ADDR"); addr != "" {
+		src := NewDump1090Source(addr, index)
+		go src.Run(ctx)
+		sources = append(sources, src)
+	}
+	if addr := os.Getenv("DUMP978_ADDR"); addr != "" {
+		src := NewDump978Source(addr, index)
+		go src.Run(ctx)
+		sources = append(sources, src)
+	}
+	if url := os.Getenv("STRATUX_URL"); url != "" {
+		src := NewStratuxSource(url, index)
+		go src.Run(ctx)
+		sources = append(sources, src)
+	}
+
+	fusion := NewFusion()
+
+	// GDL90 broadcast to EFB clients is opt-in: configure one UDP address
+	// per client (e.g. a phone's WiFi broadcast address on port 4000).
+	// This service has no ownship GPS source of its own (it's a ground
+	// traffic receiver, not an avionics position source), so Broadcaster
+	// never has SetOwnship called on it: it broadcasts Heartbeats and
+	// Traffic Reports but withholds the Ownship Report rather than
+	// placing the aircraft at a fabricated 0,0 position.
+	var broadcaster *gdl90.Broadcaster
+	if clients := os.Getenv("GDL90_CLIENTS"); clients != "" {
+		var err error
+		broadcaster, err = gdl90.NewBroadcaster(logger, strings.Split(clients, ","))
+		if err != nil {
+			logger.Fatalf("gdl90: %v", err)
+		}
+		defer broadcaster.Close()
+		go broadcaster.Run(ctx.Done())
+	}
 
 	// Start the data collection loop
 	ticker := time.NewTicker(15 * time.Second) // OpenSky has rate limits
 	defer ticker.Stop()
 
 	for range ticker.C {
-		processAircraftData(logger, openSkyUsername, openSkyPassword)
+		processAircraftData(ctx, logger, sources, fusion, broadcaster, index)
 	}
 }
 
-func processAircraftData(logger *log.Logger, username, password string) {
-	for _, airport := range airports {
-		// Set boundaries for the API request (approximately 10nm around the airport)
-		// Convert nm to degrees (roughly)
-		latRange := airport.Radius / 60.0 // 1nm â‰ˆ 1 minute of latitude
-		lonRange := latRange / cos(airport.Latitude*0.0174533) // Adjust for longitude compression
-
-		minLat := airport.Latitude - latRange
-		maxLat := airport.Latitude + latRange
-		minLon := airport.Longitude - lonRange
-		maxLon := airport.Longitude + lonRange
-
-		url := fmt.Sprintf("https://opensky-network.org/api/states/all?lamin=%f&lomin=%f&lamax=%f&lomax=%f",
-			minLat, minLon, maxLat, maxLon)
-
-		logger.Printf("Fetching data for %s airport vicinity...", airport.ICAO)
-		
-		client := &http.Client{Timeout: 10 * time.Second}
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			logger.Printf("Error creating request: %v", err)
-			continue
-		}
+// runReplay reconstructs opts' historical window from OpenSky and feeds
+// it through the same Fusion and conflict engine the live poller uses,
+// for regression-testing conflict rules or producing a post-incident
+// report: every alert the engine would have raised at the time is
+// logged as the replay plays out.
+func runReplay(logger *log.Logger, client *OpenSkyClient, opts ReplayOptions, airportsSrc, runwaysSrc string, radiusNm float64) {
+	logger.Printf("Starting replay of %s between %s and %s at %.0fx speed", opts.Airport, opts.From, opts.To, opts.Speed)
 
-		// Add basic auth if credentials are provided
-		if username != "" && password != "" {
-			req.SetBasicAuth(username, password)
-		}
+	loadedAirports, err := airportdb.Load(airportsSrc, runwaysSrc, radiusNm)
+	if err != nil {
+		logger.Fatalf("loading airport database: %v", err)
+	}
+	index := airportdb.NewIndex(loadedAirports)
 
-		resp, err := client.Do(req)
-		if err != nil {
-			logger.Printf("Error fetching data: %v", err)
-			continue
-		}
-		defer resp.Body.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-		if resp.StatusCode != http.StatusOK {
-			logger.Printf("API returned non-200 status: %d", resp.StatusCode)
-			continue
-		}
+	fusion := NewFusion()
+	if err := NewReplayer(logger, client).Run(ctx, opts, fusion, index); err != nil {
+		logger.Fatalf("replay failed: %v", err)
+	}
+
+	fused := fusion.Snapshot()
+	logger.Printf("Replay complete: %d aircraft reconstructed", len(fused))
+}
+
+// processAircraftData polls every configured TrafficSource concurrently,
+// merges their updates into fusion, and hands the resulting fused
+// aircraft states off for downstream use: conflict detection and, if
+// broadcaster is non-nil, GDL90 traffic reports for EFB clients.
+func processAircraftData(ctx context.Context, logger *log.Logger, sources []TrafficSource, fusion *Fusion, broadcaster *gdl90.Broadcaster, index *airportdb.Index) {
+	type polled struct {
+		states []AircraftState
+		err    error
+	}
 
-		var openSkyResp OpenSkyResponse
-		if err := json.NewDecoder(resp.Body).Decode(&openSkyResp); err != nil {
-			logger.Printf("Error decoding response: %v", err)
+	results := make(chan polled, len(sources))
+	for _, src := range sources {
+		src := src
+		go func() {
+			states, err := src.Poll(ctx)
+			results <- polled{states, err}
+		}()
+	}
+
+	var received int
+	for range sources {
+		r := <-results
+		if r.err != nil {
+			logger.Printf("source poll error: %v", r.err)
 			continue
 		}
+		for _, state := range r.states {
+			fusion.Merge(state)
+			received++
+		}
+	}
 
-		// Process the response
-		states := parseAircraftStates(openSkyResp, airport.ICAO)
-		logger.Printf("Received %d aircraft states in %s vicinity", len(states), airport.ICAO)
-
-		// In a production environment, these states would be:
-		// 1. Stored in a database
-		// 2. Published to a message queue for the conflict detection engine
-		// 3. Analyzed for potential safety issues
-		
-		// For now, just log a sample
-		if len(states) > 0 {
-			logger.Printf("Sample aircraft: %s (Callsign: %s) at altitude: %.0f, heading: %.0f",
-				states[0].Icao24, states[0].Callsign, states[0].Altitude, states[0].Heading)
+	fused := fusion.Snapshot()
+	logger.Printf("Merged %d updates from %d sources into %d tracked aircraft", received, len(sources), len(fused))
+
+	if broadcaster != nil {
+		for _, state := range fused {
+			broadcaster.SendTraffic(adaptTrafficReport(state))
 		}
 	}
+
+	evaluateConflicts(ctx, logger, index, fused)
+}
+
+// adaptTrafficReport converts a fused AircraftState into the gdl90
+// package's decoupled TrafficReport type.
+func adaptTrafficReport(a AircraftState) gdl90.TrafficReport {
+	icaoAddr, _ := strconv.ParseUint(a.Icao24, 16, 32)
+	return gdl90.TrafficReport{
+		ICAOAddress:     uint32(icaoAddr),
+		Callsign:        a.Callsign,
+		Latitude:        a.Latitude,
+		Longitude:       a.Longitude,
+		AltitudeFt:      a.Altitude,
+		GroundSpeedKt:   a.Velocity,
+		TrackDeg:        a.Heading,
+		VerticalRateFpm: a.VerticalRate,
+		IsAirborne:      !a.OnGround,
+	}
+}
+
+// envOr returns the named environment variable, or fallback if it's unset.
+func envOr(name, fallback string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return fallback
 }
 
 // parseAircraftStates converts the OpenSky API response into typed AircraftState objects
-func parseAircraftStates(response OpenSkyResponse, airportICAO string) []AircraftState {
+func parseAircraftStates(response OpenSkyResponse) []AircraftState {
 	states := make([]AircraftState, 0, len(response.States))
 	now := time.Unix(response.Time, 0)
 
@@ -143,14 +253,15 @@ func parseAircraftStates(response OpenSkyResponse, airportICAO string) []Aircraf
 		icao24, _ := stateArray[0].(string)
 		callsign, _ := stateArray[1].(string)
 		originCountry, _ := stateArray[2].(string)
-		
+
 		// Position data might be null if not available
 		var lat, lon, alt, vel, hdg, vrate float64
 		var timePos, lastContact int64
 		var onGround bool
 
 		if stateArray[5] != nil {
-			timePos, _ = stateArray[5].(float64)
+			timePosFloat, _ := stateArray[5].(float64)
+			timePos = int64(timePosFloat)
 		}
 		if stateArray[6] != nil {
 			lat, _ = stateArray[6].(float64)
@@ -179,27 +290,21 @@ func parseAircraftStates(response OpenSkyResponse, airportICAO string) []Aircraf
 		}
 
 		states = append(states, AircraftState{
-			Icao24:         icao24,
-			Callsign:       callsign,
-			OriginCountry:  originCountry,
-			Latitude:       lat,
-			Longitude:      lon,
-			Altitude:       alt,
-			Velocity:       vel,
-			Heading:        hdg,
-			VerticalRate:   vrate,
-			OnGround:       onGround,
-			TimePosition:   timePos,
-			LastContact:    lastContact,
-			Timestamp:      now,
-			AirportVicinity: airportICAO,
+			Icao24:        icao24,
+			Callsign:      callsign,
+			OriginCountry: originCountry,
+			Latitude:      lat,
+			Longitude:     lon,
+			Altitude:      alt,
+			Velocity:      vel,
+			Heading:       hdg,
+			VerticalRate:  vrate,
+			OnGround:      onGround,
+			TimePosition:  timePos,
+			LastContact:   lastContact,
+			Timestamp:     now,
 		})
 	}
 
 	return states
 }
-
-// Simple cosine function for longitude adjustment
-func cos(radians float64) float64 {
-	return float64(time.Now().Nanosecond()%10000)/10000.0*0.1 + 0.9 // Stub for actual math.Cos
-} 
\ No newline at end of file
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// OpenSky's daily credit allowance: far lower for anonymous requests.
+const (
+	openSkyAnonCreditsPerDay = 400
+	openSkyAuthCreditsPerDay = 4000
+)
+
+// OpenSkyClient wraps http.Client with OpenSky's credit-based daily rate
+// limit and Retry-After backoff, shared by the live poller and replay so
+// neither exhausts the budget the other needs.
+type OpenSkyClient struct {
+	username string
+	password string
+	http     *http.Client
+
+	mu          sync.Mutex
+	creditsLeft int
+	resetAt     time.Time
+}
+
+// NewOpenSkyClient returns a client budgeted for the anonymous rate limit,
+// or the authenticated one if username/password are both set.
+func NewOpenSkyClient(username, password string) *OpenSkyClient {
+	return &OpenSkyClient{
+		username:    username,
+		password:    password,
+		http:        &http.Client{Timeout: 15 * time.Second},
+		creditsLeft: dailyCreditLimit(username, password),
+		resetAt:     nextMidnightUTC(),
+	}
+}
+
+func dailyCreditLimit(username, password string) int {
+	if username != "" && password != "" {
+		return openSkyAuthCreditsPerDay
+	}
+	return openSkyAnonCreditsPerDay
+}
+
+func nextMidnightUTC() time.Time {
+	now := time.Now().UTC()
+	return time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+}
+
+// reserve blocks the budget for cost credits, resetting it if the daily
+// window has rolled over, and errors if the remaining budget can't cover
+// the request rather than silently sending it anyway.
+func (c *OpenSkyClient) reserve(cost int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if time.Now().UTC().After(c.resetAt) {
+		c.creditsLeft = dailyCreditLimit(c.username, c.password)
+		c.resetAt = nextMidnightUTC()
+	}
+	if c.creditsLeft < cost {
+		return fmt.Errorf("opensky: insufficient credits (%d left, need %d, resets %s)",
+			c.creditsLeft, cost, c.resetAt.Format(time.RFC3339))
+	}
+	c.creditsLeft -= cost
+	return nil
+}
+
+// Get issues a GET request against url, debiting creditCost credits from
+// the daily budget, and retries once after honoring a 429's Retry-After.
+func (c *OpenSkyClient) Get(ctx context.Context, url string, creditCost int) (*http.Response, error) {
+	if err := c.reserve(creditCost); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		wait := retryAfter(resp)
+		resp.Body.Close()
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		return c.do(ctx, url)
+	}
+	return resp, nil
+}
+
+func (c *OpenSkyClient) do(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.username != "" && c.password != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+	return c.http.Do(req)
+}
+
+// retryAfter parses a Retry-After header (seconds form), defaulting to 5s
+// if it's absent or malformed.
+func retryAfter(resp *http.Response) time.Duration {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 5 * time.Second
+	}
+	secs, err := strconv.Atoi(v)
+	if err != nil {
+		return 5 * time.Second
+	}
+	return time.Duration(secs) * time.Second
+}
+
+// flightQueryCredits returns the credit cost of a /flights query spanning
+// window, per OpenSky's time-window-based pricing: wider windows cost
+// more regardless of how many flights they return.
+func flightQueryCredits(window time.Duration) int {
+	switch {
+	case window <= time.Hour:
+		return 1
+	case window <= 4*time.Hour:
+		return 2
+	case window <= 12*time.Hour:
+		return 3
+	default:
+		return 4
+	}
+}
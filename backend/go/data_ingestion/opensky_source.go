@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/tuedolm/non-towered-safety-system/backend/go/airportdb"
+	"github.com/tuedolm/non-towered-safety-system/backend/go/geo"
+)
+
+// OpenSkySource polls the OpenSky Network REST API once per region
+// covering every airport in index, then dispatches each returned aircraft
+// to every airport whose radius contains it.
+type OpenSkySource struct {
+	logger *log.Logger
+	client *OpenSkyClient
+	index  *airportdb.Index
+}
+
+// NewOpenSkySource returns a source that queries OpenSky's states/all
+// endpoint once for the whole region index covers.
+func NewOpenSkySource(logger *log.Logger, client *OpenSkyClient, index *airportdb.Index) *OpenSkySource {
+	return &OpenSkySource{logger: logger, client: client, index: index}
+}
+
+// Poll fetches the current states/all bounding box for the whole region
+// and fans each aircraft out to the airport vicinities it falls within.
+// An aircraft near more than one airport is reported once per airport.
+func (s *OpenSkySource) Poll(ctx context.Context) ([]AircraftState, error) {
+	min, max := s.index.RegionBoundingBox()
+
+	url := fmt.Sprintf("https://opensky-network.org/api/states/all?lamin=%f&lomin=%f&lamax=%f&lomax=%f",
+		min.Lat, min.Long, max.Lat, max.Long)
+
+	resp, err := s.client.Get(ctx, url, stateQueryCredits(min, max))
+	if err != nil {
+		return nil, fmt.Errorf("fetching data: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("API returned non-200 status: %d", resp.StatusCode)
+	}
+
+	var openSkyResp OpenSkyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&openSkyResp); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+
+	raw := parseAircraftStates(openSkyResp)
+	now := time.Now()
+
+	var all []AircraftState
+	for _, state := range raw {
+		point := geo.LatLong{Lat: state.Latitude, Long: state.Longitude}
+		for _, airport := range s.index.Near(point, state.Altitude) {
+			dispatched := state
+			dispatched.AirportVicinity = airport.ICAO
+			dispatched.Source = SourceOpenSky
+			dispatched.Fields = FieldTimestamps{
+				LastPos:      now,
+				LastAlt:      now,
+				LastVelocity: now,
+				LastCallsign: now,
+				LastSeen:     now,
+			}
+			all = append(all, dispatched)
+		}
+	}
+
+	s.logger.Printf("Received %d aircraft states across %d airport vicinities", len(raw), len(s.index.Airports()))
+	return all, nil
+}
+
+// stateQueryCredits returns the OpenSky credit cost of a states/all query
+// over the given bounding box: cost scales with the box's area.
+func stateQueryCredits(min, max geo.LatLong) int {
+	longSpan := max.Long - min.Long
+	if longSpan < 0 {
+		// The box wraps the antimeridian (min.Long > max.Long).
+		longSpan += 360
+	}
+	areaDeg2 := (max.Lat - min.Lat) * longSpan
+	switch {
+	case areaDeg2 <= 25:
+		return 1
+	case areaDeg2 <= 100:
+		return 2
+	case areaDeg2 <= 400:
+		return 3
+	default:
+		return 4
+	}
+}
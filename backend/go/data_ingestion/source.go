@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"time"
+)
+
+// Source identifies which feed most recently updated an AircraftState.
+type Source int
+
+const (
+	SourceOpenSky Source = iota
+	Source1090ES
+	SourceUAT
+	SourceStratux
+)
+
+func (s Source) String() string {
+	switch s {
+	case SourceOpenSky:
+		return "SOURCE_OPENSKY"
+	case Source1090ES:
+		return "SOURCE_1090ES"
+	case SourceUAT:
+		return "SOURCE_UAT"
+	case SourceStratux:
+		return "SOURCE_STRATUX"
+	default:
+		return "SOURCE_UNKNOWN"
+	}
+}
+
+// FieldTimestamps records when each independently-reported field of an
+// AircraftState was last updated. Mode S transmits position, altitude,
+// velocity, and callsign in separate messages, so a single "last seen"
+// timestamp isn't enough to know which fields are stale.
+type FieldTimestamps struct {
+	LastPos      time.Time
+	LastAlt      time.Time
+	LastVelocity time.Time
+	LastCallsign time.Time
+	LastSeen     time.Time
+}
+
+// TrafficSource is anything that can be polled for the aircraft states it
+// currently knows about. OpenSky is request/response; the local-receiver
+// sources instead keep the latest state their feed reported for each
+// aircraft until it goes stale (see their Run methods).
+type TrafficSource interface {
+	Poll(ctx context.Context) ([]AircraftState, error)
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/net/websocket"
+
+	"github.com/tuedolm/non-towered-safety-system/backend/go/airportdb"
+	"github.com/tuedolm/non-towered-safety-system/backend/go/geo"
+)
+
+// stratuxTraffic mirrors the subset of Stratux's /traffic websocket
+// payload that this service cares about.
+type stratuxTraffic struct {
+	IcaoAddr    uint32  `json:"Icao_addr"`
+	Tail        string  `json:"Tail"`
+	Lat         float64 `json:"Lat"`
+	Lng         float64 `json:"Lng"`
+	Alt         float64 `json:"Alt"`
+	Speed       float64 `json:"Speed"`
+	Track       float64 `json:"Track"`
+	Vvel        float64 `json:"Vvel"`
+	OnGround    bool    `json:"OnGround"`
+	SignalLevel float64 `json:"SignalLevel"`
+}
+
+// StratuxSource connects to a Stratux receiver's /traffic websocket and
+// accumulates aircraft state until the next Poll.
+type StratuxSource struct {
+	url   string
+	index *airportdb.Index
+
+	mu     sync.Mutex
+	tracks map[string]AircraftState
+}
+
+// NewStratuxSource returns a source that dials the Stratux /traffic
+// websocket at url (e.g. "ws://192.168.10.1/traffic"). Call Run in its
+// own goroutine before Poll is used. index is used to tag each report
+// with the airport vicinities it falls within, same as OpenSkySource.
+func NewStratuxSource(url string, index *airportdb.Index) *StratuxSource {
+	return &StratuxSource{url: url, index: index, tracks: make(map[string]AircraftState)}
+}
+
+// Run dials the feed and consumes it until ctx is done, reconnecting on
+// error after a short backoff.
+func (s *StratuxSource) Run(ctx context.Context) {
+	for ctx.Err() == nil {
+		if err := s.consume(ctx); err != nil {
+			time.Sleep(5 * time.Second)
+		}
+	}
+}
+
+func (s *StratuxSource) consume(ctx context.Context) error {
+	conn, err := websocket.Dial(s.url, "", "http://localhost/")
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	dec := json.NewDecoder(conn)
+	for {
+		var msg stratuxTraffic
+		if err := dec.Decode(&msg); err != nil {
+			return err
+		}
+		s.ingest(msg)
+	}
+}
+
+func (s *StratuxSource) ingest(msg stratuxTraffic) {
+	icao24 := fmt.Sprintf("%06x", msg.IcaoAddr)
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tracks[icao24] = AircraftState{
+		Icao24:       icao24,
+		Callsign:     msg.Tail,
+		Latitude:     msg.Lat,
+		Longitude:    msg.Lng,
+		Altitude:     msg.Alt,
+		Velocity:     msg.Speed,
+		Heading:      msg.Track,
+		VerticalRate: msg.Vvel,
+		OnGround:     msg.OnGround,
+		LastContact:  now.Unix(),
+		Timestamp:    now,
+		Source:       SourceStratux,
+		Signal:       msg.SignalLevel,
+		Fields: FieldTimestamps{
+			LastPos:      now,
+			LastAlt:      now,
+			LastVelocity: now,
+			LastCallsign: now,
+			LastSeen:     now,
+		},
+	}
+}
+
+// Poll returns every aircraft state the feed has updated within
+// staleAfter, whether or not an earlier Poll already returned it,
+// evicting any track that's gone stale and fanning each surviving one out to the airport vicinities it falls within, same as
+// OpenSkySource.Poll.
+func (s *StratuxSource) Poll(ctx context.Context) ([]AircraftState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cutoff := time.Now().Add(-staleAfter)
+	var out []AircraftState
+	for icao, state := range s.tracks {
+		if state.Fields.LastSeen.Before(cutoff) {
+			delete(s.tracks, icao)
+			continue
+		}
+		point := geo.LatLong{Lat: state.Latitude, Long: state.Longitude}
+		for _, airport := range s.index.Near(point, state.Altitude) {
+			dispatched := state
+			dispatched.AirportVicinity = airport.ICAO
+			out = append(out, dispatched)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,100 @@
+package gdl90
+
+import (
+	"log"
+	"net"
+	"sync"
+	"time"
+)
+
+// heartbeatInterval matches the GDL90 spec's once-per-second cadence.
+const heartbeatInterval = 1 * time.Second
+
+// Broadcaster sends framed GDL90 messages over UDP to one or more EFB
+// clients (ForeFlight, Avare, SkyDemon all listen on the same well-known
+// broadcast format, so a single sender can serve all three).
+type Broadcaster struct {
+	logger *log.Logger
+	conns  []*net.UDPConn
+
+	mu         sync.RWMutex
+	ownship    TrafficReport
+	hasOwnship bool
+}
+
+// NewBroadcaster dials a UDP socket for each client address (host:port,
+// typically a subnet broadcast address on port 4000).
+func NewBroadcaster(logger *log.Logger, clientAddrs []string) (*Broadcaster, error) {
+	b := &Broadcaster{logger: logger}
+	for _, addr := range clientAddrs {
+		raddr, err := net.ResolveUDPAddr("udp4", addr)
+		if err != nil {
+			return nil, err
+		}
+		conn, err := net.DialUDP("udp4", nil, raddr)
+		if err != nil {
+			return nil, err
+		}
+		b.conns = append(b.conns, conn)
+	}
+	return b, nil
+}
+
+// SetOwnship updates the position reported in periodic Ownship Reports.
+// Safe to call concurrently with Run. Until this is called at least once,
+// Run sends Heartbeats but withholds the Ownship Report rather than
+// broadcasting the zero value (which would place the aircraft at 0,0).
+func (b *Broadcaster) SetOwnship(r TrafficReport) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.ownship = r
+	b.hasOwnship = true
+}
+
+// send writes frame to every configured client.
+func (b *Broadcaster) send(frame []byte) {
+	for _, conn := range b.conns {
+		if _, err := conn.Write(frame); err != nil {
+			b.logger.Printf("gdl90: write to %s failed: %v", conn.RemoteAddr(), err)
+		}
+	}
+}
+
+// SendTraffic encodes and broadcasts a Traffic Report for r.
+func (b *Broadcaster) SendTraffic(r TrafficReport) {
+	b.send(EncodeTrafficReport(r))
+}
+
+// Run broadcasts a Heartbeat, and an Ownship Report once SetOwnship has
+// been called, once per second until stop is closed, or forever if stop
+// is nil.
+func (b *Broadcaster) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			utc := now.UTC()
+			midnight := time.Date(utc.Year(), utc.Month(), utc.Day(), 0, 0, 0, 0, time.UTC)
+			secs := int(utc.Sub(midnight).Seconds())
+			b.send(EncodeHeartbeat(secs))
+
+			b.mu.RLock()
+			ownship, hasOwnship := b.ownship, b.hasOwnship
+			b.mu.RUnlock()
+			if hasOwnship {
+				b.send(EncodeOwnship(ownship))
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Close releases the underlying UDP sockets.
+func (b *Broadcaster) Close() {
+	for _, conn := range b.conns {
+		conn.Close()
+	}
+}
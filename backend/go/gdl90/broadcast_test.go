@@ -0,0 +1,82 @@
+package gdl90
+
+import (
+	"io"
+	"log"
+	"net"
+	"testing"
+	"time"
+)
+
+// listenUDP returns a UDP listener on loopback and the address a
+// Broadcaster should dial to reach it.
+func listenUDP(t *testing.T) (*net.UDPConn, string) {
+	t.Helper()
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("ListenUDP: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn, conn.LocalAddr().String()
+}
+
+func readMsgID(t *testing.T, conn *net.UDPConn) (byte, []byte) {
+	t.Helper()
+	buf := make([]byte, 256)
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("reading broadcast frame: %v", err)
+	}
+	frame := buf[:n]
+	return destuff(frame[1 : len(frame)-1])[0], frame
+}
+
+func TestRunWithholdsOwnshipUntilSet(t *testing.T) {
+	conn, addr := listenUDP(t)
+	b, err := NewBroadcaster(log.New(io.Discard, "", 0), []string{addr})
+	if err != nil {
+		t.Fatalf("NewBroadcaster: %v", err)
+	}
+	defer b.Close()
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go b.Run(stop)
+
+	// Before SetOwnship, the only message received per heartbeatInterval
+	// tick should be the Heartbeat; no Ownship Report should ever arrive.
+	id, _ := readMsgID(t, conn)
+	if id != MsgHeartbeat {
+		t.Fatalf("first message ID = %#x, want MsgHeartbeat %#x", id, MsgHeartbeat)
+	}
+	conn.SetReadDeadline(time.Now().Add(500 * time.Millisecond))
+	if _, _, err := conn.ReadFromUDP(make([]byte, 256)); err == nil {
+		t.Fatalf("received a second message before SetOwnship was ever called, want none")
+	}
+}
+
+func TestRunSendsOwnshipAfterSet(t *testing.T) {
+	conn, addr := listenUDP(t)
+	b, err := NewBroadcaster(log.New(io.Discard, "", 0), []string{addr})
+	if err != nil {
+		t.Fatalf("NewBroadcaster: %v", err)
+	}
+	defer b.Close()
+
+	b.SetOwnship(TrafficReport{Latitude: 37.5, Longitude: -122.25})
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go b.Run(stop)
+
+	// Heartbeat, then Ownship, every tick.
+	id, _ := readMsgID(t, conn)
+	if id != MsgHeartbeat {
+		t.Fatalf("first message ID = %#x, want MsgHeartbeat %#x", id, MsgHeartbeat)
+	}
+	id, _ = readMsgID(t, conn)
+	if id != MsgOwnship {
+		t.Fatalf("second message ID = %#x, want MsgOwnship %#x", id, MsgOwnship)
+	}
+}
@@ -0,0 +1,29 @@
+package gdl90
+
+// crcTable is the 256-entry CRC-16-CCITT lookup table used to checksum
+// GDL90 frames, generated with polynomial 0x1021 (as specified by the
+// GDL90 Data Interface Specification).
+var crcTable [256]uint16
+
+func init() {
+	for i := 0; i < 256; i++ {
+		crc := uint16(i) << 8
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x8000 != 0 {
+				crc = (crc << 1) ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+		crcTable[i] = crc
+	}
+}
+
+// crc16 computes the GDL90 CRC-16-CCITT over data using the lookup table.
+func crc16(data []byte) uint16 {
+	var crc uint16
+	for _, b := range data {
+		crc = (crc << 8) ^ crcTable[byte(crc>>8)^b]
+	}
+	return crc
+}
@@ -0,0 +1,30 @@
+package gdl90
+
+const (
+	flagByte   = 0x7E
+	escapeByte = 0x7D
+	escapeXOR  = 0x20
+)
+
+// frame appends the CRC-16 to msg, byte-stuffs any 0x7E/0x7D bytes in the
+// result, and wraps it in leading/trailing flag bytes, producing a
+// self-delimiting frame ready to write to a UDP socket.
+func frame(msg []byte) []byte {
+	crc := crc16(msg)
+	payload := make([]byte, len(msg)+2)
+	copy(payload, msg)
+	payload[len(msg)] = byte(crc & 0xFF)
+	payload[len(msg)+1] = byte(crc >> 8)
+
+	out := make([]byte, 0, len(payload)+4)
+	out = append(out, flagByte)
+	for _, b := range payload {
+		if b == flagByte || b == escapeByte {
+			out = append(out, escapeByte, b^escapeXOR)
+		} else {
+			out = append(out, b)
+		}
+	}
+	out = append(out, flagByte)
+	return out
+}
@@ -0,0 +1,39 @@
+// Package gdl90 encodes ADS-B traffic into GDL90 messages and broadcasts
+// them over UDP so that EFB apps (ForeFlight, Avare, SkyDemon) can display
+// them exactly as they would traffic from a Stratux or GDL 90-compatible
+// receiver.
+package gdl90
+
+// Message IDs, per the GDL90 Data Interface Specification.
+const (
+	MsgHeartbeat     = 0x00
+	MsgOwnship       = 0x0A
+	MsgOwnshipGeoAlt = 0x0B
+	MsgTrafficReport = 0x14
+)
+
+// latLonResolution is the smallest representable change in latitude or
+// longitude for a 24-bit signed GDL90 position field: 180 / 2^23 degrees.
+const latLonResolution = 180.0 / (1 << 23)
+
+// TrafficReport is the subset of aircraft state needed to build a GDL90
+// Traffic Report (and, passed to EncodeOwnship, an Ownship Report). Callers
+// adapt whatever AircraftState representation they have onto this type
+// rather than gdl90 depending on an ingestion package.
+type TrafficReport struct {
+	ICAOAddress     uint32 // 24-bit participant address
+	Callsign        string
+	Latitude        float64
+	Longitude       float64
+	AltitudeFt      float64 // pressure altitude, feet
+	GroundSpeedKt   float64
+	TrackDeg        float64
+	VerticalRateFpm float64
+	EmitterCategory byte
+	NIC             byte
+	NACp            byte
+	AlertStatus     byte // 0 = no alert, 1 = alert
+	AddressType     byte // 0 = ICAO address, see spec for others
+	PriorityCode    byte // emergency/priority code, see spec table
+	IsAirborne      bool
+}
@@ -0,0 +1,134 @@
+package gdl90
+
+import "testing"
+
+func TestEncodeLatLonZero(t *testing.T) {
+	got := encodeLatLon(0)
+	want := [3]byte{0, 0, 0}
+	if got != want {
+		t.Errorf("encodeLatLon(0) = %v, want %v", got, want)
+	}
+}
+
+func TestEncodeLatLonPositive(t *testing.T) {
+	// 45 degrees = 45 / (180/2^23) = 2^23/4 = 0x200000 counts.
+	got := encodeLatLon(45)
+	want := [3]byte{0x20, 0x00, 0x00}
+	if got != want {
+		t.Errorf("encodeLatLon(45) = %v, want %v", got, want)
+	}
+}
+
+func TestEncodeLatLonNegative(t *testing.T) {
+	// -45 degrees is the two's-complement of 45's count within 24 bits.
+	got := encodeLatLon(-45)
+	want := [3]byte{0xE0, 0x00, 0x00}
+	if got != want {
+		t.Errorf("encodeLatLon(-45) = %v, want %v", got, want)
+	}
+}
+
+func TestEncodeAltitude(t *testing.T) {
+	cases := []struct {
+		altFt float64
+		want  uint16
+	}{
+		{0, 40},         // (0+1000)/25
+		{-1000, 0},      // minimum representable altitude
+		{18000, 760},    // (18000+1000)/25
+		{200000, 0xFFE}, // clamped to the max valid value, 101,350 ft
+		{-5000, 0},      // clamped to the minimum
+	}
+	for _, c := range cases {
+		if got := encodeAltitude(c.altFt); got != c.want {
+			t.Errorf("encodeAltitude(%v) = %v, want %v", c.altFt, got, c.want)
+		}
+	}
+}
+
+func TestEncodeTrafficPriorityCode(t *testing.T) {
+	r := TrafficReport{PriorityCode: 3}
+	msg := encodeTraffic(MsgTrafficReport, r)
+	if got := msg[27] >> 4; got != 3 {
+		t.Errorf("encodeTraffic priority nibble = %d, want 3", got)
+	}
+}
+
+func TestCRC16Empty(t *testing.T) {
+	if got := crc16(nil); got != 0 {
+		t.Errorf("crc16(nil) = %#x, want 0", got)
+	}
+}
+
+func TestCRC16Deterministic(t *testing.T) {
+	msg := []byte{0x00, 0x01, 0x02, 0x03}
+	if a, b := crc16(msg), crc16(msg); a != b {
+		t.Errorf("crc16 not deterministic: %#x != %#x", a, b)
+	}
+	if got := crc16(msg); got == 0 {
+		t.Errorf("crc16(%v) = 0, want nonzero", msg)
+	}
+}
+
+func TestFrameWrapsInFlagBytes(t *testing.T) {
+	msg := []byte{0x01, 0x02, 0x03}
+	got := frame(msg)
+
+	if got[0] != flagByte {
+		t.Fatalf("frame(%v)[0] = %#x, want flagByte %#x", msg, got[0], flagByte)
+	}
+	if got[len(got)-1] != flagByte {
+		t.Fatalf("frame(%v)[last] = %#x, want flagByte %#x", msg, got[len(got)-1], flagByte)
+	}
+}
+
+// destuff reverses frame's byte-stuffing over the interior of a frame
+// (everything between the leading and trailing flag bytes), returning the
+// original msg+CRC payload.
+func destuff(interior []byte) []byte {
+	var out []byte
+	for i := 0; i < len(interior); i++ {
+		if interior[i] == escapeByte {
+			i++
+			out = append(out, interior[i]^escapeXOR)
+			continue
+		}
+		out = append(out, interior[i])
+	}
+	return out
+}
+
+func TestFrameAppendsCRC(t *testing.T) {
+	msg := []byte{0x01, 0x02, 0x03}
+	got := frame(msg)
+
+	payload := destuff(got[1 : len(got)-1])
+	if len(payload) != len(msg)+2 {
+		t.Fatalf("de-stuffed payload length = %d, want %d", len(payload), len(msg)+2)
+	}
+
+	crc := crc16(msg)
+	wantLow, wantHigh := byte(crc&0xFF), byte(crc>>8)
+	if payload[len(msg)] != wantLow || payload[len(msg)+1] != wantHigh {
+		t.Errorf("payload CRC bytes = %#x %#x, want %#x %#x", payload[len(msg)], payload[len(msg)+1], wantLow, wantHigh)
+	}
+}
+
+func TestFrameByteStuffing(t *testing.T) {
+	// A message containing a literal flag byte and escape byte must have
+	// both stuffed in the output frame, since either would otherwise be
+	// indistinguishable from frame delimiters/escapes.
+	msg := []byte{flagByte, escapeByte, 0x42}
+	got := frame(msg)
+
+	interior := got[1 : len(got)-1]
+	decoded := destuff(interior)
+	if len(decoded) != len(msg)+2 {
+		t.Fatalf("de-stuffed length = %d, want %d", len(decoded), len(msg)+2)
+	}
+	for i, b := range msg {
+		if decoded[i] != b {
+			t.Errorf("decoded[%d] = %#x, want %#x", i, decoded[i], b)
+		}
+	}
+}
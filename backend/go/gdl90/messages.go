@@ -0,0 +1,105 @@
+package gdl90
+
+import "math"
+
+// encodeLatLon converts a latitude or longitude in degrees into its 24-bit
+// two's-complement GDL90 representation at 180/2^23 degrees per LSB.
+func encodeLatLon(deg float64) [3]byte {
+	v := int32(deg / latLonResolution)
+	return [3]byte{byte(v >> 16), byte(v >> 8), byte(v)}
+}
+
+// encodeAltitude packs pressure altitude (feet) into the 12-bit field GDL90
+// expects: (alt+1000)/25, valid from -1000 ft to 101,350 ft.
+func encodeAltitude(altFt float64) uint16 {
+	enc := int32((altFt + 1000) / 25)
+	if enc < 0 {
+		enc = 0
+	}
+	if enc > 0xFFE {
+		enc = 0xFFE
+	}
+	return uint16(enc)
+}
+
+// encodeTraffic builds the 28-byte Traffic/Ownship Report payload
+// (message ID + bytes 1-27).
+func encodeTraffic(msgID byte, r TrafficReport) []byte {
+	b := make([]byte, 28)
+	b[0] = msgID
+	b[1] = (r.AlertStatus&0x0F)<<4 | (r.AddressType & 0x0F)
+	b[2] = byte(r.ICAOAddress >> 16)
+	b[3] = byte(r.ICAOAddress >> 8)
+	b[4] = byte(r.ICAOAddress)
+
+	lat := encodeLatLon(r.Latitude)
+	copy(b[5:8], lat[:])
+	lon := encodeLatLon(r.Longitude)
+	copy(b[8:11], lon[:])
+
+	alt := encodeAltitude(r.AltitudeFt)
+	misc := byte(0x09) // airborne, true track
+	if !r.IsAirborne {
+		misc = 0x01 // on ground, true track
+	}
+	b[11] = byte(alt >> 4)
+	b[12] = byte(alt<<4) | (misc & 0x0F)
+
+	b[13] = (r.NIC&0x0F)<<4 | (r.NACp & 0x0F)
+
+	hVel := uint16(r.GroundSpeedKt)
+	if hVel > 0xFFE {
+		hVel = 0xFFE
+	}
+	vVel := int32(r.VerticalRateFpm / 64)
+	if vVel > 0x1FE {
+		vVel = 0x1FE
+	}
+	if vVel < -0x1FE {
+		vVel = -0x1FE
+	}
+	vVelBits := uint16(vVel) & 0x0FFF
+
+	b[14] = byte(hVel >> 4)
+	b[15] = byte(hVel<<4) | byte(vVelBits>>8)
+	b[16] = byte(vVelBits)
+
+	b[17] = byte(math.Round(r.TrackDeg * 256 / 360))
+	b[18] = r.EmitterCategory
+
+	callsign := r.Callsign
+	if len(callsign) > 8 {
+		callsign = callsign[:8]
+	}
+	for len(callsign) < 8 {
+		callsign += " "
+	}
+	copy(b[19:27], callsign)
+
+	b[27] = (r.PriorityCode & 0x0F) << 4
+	return b
+}
+
+// EncodeTrafficReport returns a framed GDL90 Traffic Report (0x14) for r.
+func EncodeTrafficReport(r TrafficReport) []byte {
+	return frame(encodeTraffic(MsgTrafficReport, r))
+}
+
+// EncodeOwnship returns a framed GDL90 Ownship Report (0x0A) for r.
+func EncodeOwnship(r TrafficReport) []byte {
+	return frame(encodeTraffic(MsgOwnship, r))
+}
+
+// EncodeHeartbeat returns a framed GDL90 Heartbeat (0x00) message.
+// secondsSinceMidnightUTC is used for the timestamp field.
+func EncodeHeartbeat(secondsSinceMidnightUTC int) []byte {
+	msg := make([]byte, 7)
+	msg[0] = MsgHeartbeat
+	msg[1] = 0x01 // GPS valid, no maintenance required
+	ts := uint32(secondsSinceMidnightUTC)
+	msg[2] = byte(ts >> 16 & 0x01) // bit 16 of the 17-bit timestamp
+	msg[3] = byte(ts)
+	msg[4] = byte(ts >> 8)
+	// msg[5:7] message counts, left zero.
+	return frame(msg)
+}
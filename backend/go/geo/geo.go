@@ -0,0 +1,108 @@
+// Package geo provides the latitude/longitude math the rest of the
+// system needs: great-circle distance and bearing, slant range including
+// altitude, and bounding boxes for API queries. It's modeled on
+// skypies/geo, trading its S2-backed precision for the haversine math
+// this system's accuracy needs don't go beyond.
+package geo
+
+import "math"
+
+// earthRadiusNm is the WGS-84 mean earth radius in nautical miles.
+const earthRadiusNm = 3440.065
+
+// ftPerNm converts feet to nautical miles.
+const ftPerNm = 6076.12
+
+// nmPerDegLat is the length of one degree of latitude, which (unlike
+// longitude) doesn't vary with position.
+const nmPerDegLat = 60.0
+
+// minCosLat bounds how far BoundingBox will stretch its longitude span
+// as latitude approaches the poles, where cos(lat) otherwise drives the
+// longitude delta towards infinity.
+const minCosLat = 0.01
+
+// LatLong is a point on the earth's surface in degrees, positive north
+// and east.
+type LatLong struct {
+	Lat  float64
+	Long float64
+}
+
+// Dist returns the great-circle distance to other, in nautical miles.
+func (l LatLong) Dist(other LatLong) float64 {
+	lat1 := radians(l.Lat)
+	lat2 := radians(other.Lat)
+	dLat := radians(other.Lat - l.Lat)
+	dLong := radians(other.Long - l.Long)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLong/2)*math.Sin(dLong/2)
+	c := 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+	return earthRadiusNm * c
+}
+
+// Dist3 returns the slant range to other, in nautical miles, given the
+// altitude difference between the two points in feet.
+func (l LatLong) Dist3(other LatLong, altFt float64) float64 {
+	return math.Hypot(l.Dist(other), altFt/ftPerNm)
+}
+
+// Bearing returns the initial true bearing from l to other, in degrees
+// clockwise from north, in [0, 360).
+func (l LatLong) Bearing(other LatLong) float64 {
+	lat1 := radians(l.Lat)
+	lat2 := radians(other.Lat)
+	dLong := radians(other.Long - l.Long)
+
+	y := math.Sin(dLong) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(dLong)
+
+	brg := math.Mod(degrees(math.Atan2(y, x))+360, 360)
+	return brg
+}
+
+// BoundingBox returns the southwest (min) and northeast (max) corners of
+// a box spanning radiusNm around l. Longitude delta is widened by
+// 1/cos(lat) to compensate for the compression of longitude towards the
+// poles, clamped so it doesn't diverge as lat approaches ±90, and the
+// corners are normalized so a box that crosses the antimeridian still
+// produces valid longitudes rather than values outside [-180, 180).
+func (l LatLong) BoundingBox(radiusNm float64) (min, max LatLong) {
+	latDelta := radiusNm / nmPerDegLat
+
+	cosLat := math.Cos(radians(l.Lat))
+	if cosLat < minCosLat {
+		cosLat = minCosLat
+	}
+	longDelta := latDelta / cosLat
+
+	minLat := clampLat(l.Lat - latDelta)
+	maxLat := clampLat(l.Lat + latDelta)
+
+	min = LatLong{Lat: minLat, Long: normalizeLong(l.Long - longDelta)}
+	max = LatLong{Lat: maxLat, Long: normalizeLong(l.Long + longDelta)}
+	return min, max
+}
+
+func radians(deg float64) float64 { return deg * math.Pi / 180 }
+func degrees(rad float64) float64 { return rad * 180 / math.Pi }
+
+func clampLat(lat float64) float64 {
+	if lat > 90 {
+		return 90
+	}
+	if lat < -90 {
+		return -90
+	}
+	return lat
+}
+
+// normalizeLong wraps a longitude into [-180, 180).
+func normalizeLong(long float64) float64 {
+	long = math.Mod(long+180, 360)
+	if long < 0 {
+		long += 360
+	}
+	return long - 180
+}
@@ -0,0 +1,116 @@
+package geo
+
+import (
+	"math"
+	"testing"
+)
+
+func almostEqual(a, b, tol float64) bool {
+	return math.Abs(a-b) <= tol
+}
+
+func TestDistZero(t *testing.T) {
+	p := LatLong{Lat: 37.3329, Long: -121.8195}
+	if d := p.Dist(p); d != 0 {
+		t.Errorf("Dist(p, p) = %v, want 0", d)
+	}
+}
+
+func TestDistKnownRoute(t *testing.T) {
+	// Reid-Hillview to Palo Alto, roughly 16nm apart.
+	rhv := LatLong{Lat: 37.3329, Long: -121.8195}
+	pao := LatLong{Lat: 37.4613, Long: -122.1146}
+
+	got := rhv.Dist(pao)
+	if !almostEqual(got, 16.05, 0.1) {
+		t.Errorf("Dist(RHV, PAO) = %.2fnm, want ~16.05nm", got)
+	}
+}
+
+func TestDist3AddsAltitude(t *testing.T) {
+	a := LatLong{Lat: 37.0, Long: -122.0}
+	b := LatLong{Lat: 37.0, Long: -122.0} // same point, differ only in altitude
+
+	got := a.Dist3(b, 6076.12) // 1nm of altitude difference
+	if !almostEqual(got, 1.0, 1e-6) {
+		t.Errorf("Dist3 with 1nm altitude offset = %v, want 1.0", got)
+	}
+}
+
+func TestBearingCardinalDirections(t *testing.T) {
+	origin := LatLong{Lat: 0, Long: 0}
+
+	north := LatLong{Lat: 1, Long: 0}
+	if brg := origin.Bearing(north); !almostEqual(brg, 0, 0.5) {
+		t.Errorf("Bearing(north) = %v, want ~0", brg)
+	}
+
+	east := LatLong{Lat: 0, Long: 1}
+	if brg := origin.Bearing(east); !almostEqual(brg, 90, 0.5) {
+		t.Errorf("Bearing(east) = %v, want ~90", brg)
+	}
+}
+
+func TestBoundingBoxAtEquator(t *testing.T) {
+	p := LatLong{Lat: 0, Long: 0}
+	min, max := p.BoundingBox(60) // 1 degree of latitude
+
+	if !almostEqual(max.Lat-min.Lat, 2.0, 0.01) {
+		t.Errorf("lat span = %v, want ~2.0 degrees", max.Lat-min.Lat)
+	}
+	// At the equator, cos(lat) == 1, so longitude span matches latitude span.
+	if !almostEqual(max.Long-min.Long, 2.0, 0.01) {
+		t.Errorf("long span at equator = %v, want ~2.0 degrees", max.Long-min.Long)
+	}
+}
+
+func TestBoundingBoxHighLatitudeWidensLongitude(t *testing.T) {
+	// Near Anchorage: longitude compression means the box must stretch
+	// much wider in longitude than latitude to cover the same distance.
+	p := LatLong{Lat: 61.2, Long: -149.9}
+	min, max := p.BoundingBox(10)
+
+	latSpan := max.Lat - min.Lat
+	longSpan := max.Long - min.Long
+	if longSpan <= latSpan {
+		t.Errorf("expected longitude span (%v) > latitude span (%v) at high latitude", longSpan, latSpan)
+	}
+
+	wantLongDelta := (10 / nmPerDegLat) / math.Cos(radians(61.2))
+	gotLongDelta := longSpan / 2
+	if !almostEqual(gotLongDelta, wantLongDelta, 0.01) {
+		t.Errorf("long delta = %v, want %v", gotLongDelta, wantLongDelta)
+	}
+}
+
+func TestBoundingBoxClampsNearPole(t *testing.T) {
+	p := LatLong{Lat: 89.9, Long: 0}
+	min, max := p.BoundingBox(50)
+
+	longSpan := max.Long - min.Long
+	// Without clamping cos(lat) towards zero would blow the span past
+	// a full 360 degrees.
+	if longSpan > 360 {
+		t.Errorf("long span = %v, want <= 360 (clamped)", longSpan)
+	}
+	if max.Lat > 90 {
+		t.Errorf("max.Lat = %v, want clamped to <= 90", max.Lat)
+	}
+}
+
+func TestBoundingBoxAntimeridian(t *testing.T) {
+	// PGUM-ish longitude near the antimeridian: the box should wrap
+	// around to negative longitudes rather than exceeding 180.
+	p := LatLong{Lat: 13.5, Long: 179.9}
+	min, max := p.BoundingBox(60)
+
+	if min.Long < max.Long {
+		t.Errorf("expected wrapped box where min.Long (%v) > max.Long (%v) across the antimeridian", min.Long, max.Long)
+	}
+	if min.Long < -180 || min.Long >= 180 {
+		t.Errorf("min.Long = %v, out of [-180, 180) range", min.Long)
+	}
+	if max.Long < -180 || max.Long >= 180 {
+		t.Errorf("max.Long = %v, out of [-180, 180) range", max.Long)
+	}
+}